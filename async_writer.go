@@ -0,0 +1,151 @@
+package ringo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClosed is returned when writing to a closed AsyncWriter.
+var ErrClosed = errors.New("ringo: writer closed")
+
+var _ io.WriteCloser = &AsyncWriter{}
+
+// AsyncWriter is a non-blocking io.Writer. Writes are copied into a ManyToOne
+// ring buffer and flushed to the underlying writer by a background
+// go-routine. If the underlying writer is slower than writers, oldest writes
+// are dropped.
+type AsyncWriter struct {
+	w      io.Writer
+	waiter Waiter[[]byte]
+	onDrop func(int)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// Held by writers while pushing so Close can't complete in between.
+	closeMu sync.RWMutex
+	closed  bool
+
+	// Number of Write() calls.
+	written atomic.Uint64
+
+	mu   sync.Mutex
+	cond sync.Cond
+	// Number of writes flushed or dropped.
+	processed uint64
+	err       error
+}
+
+// NewAsyncWriter returns a new AsyncWriter that writes to w using a ring
+// buffer of the given size. onDrop, if not nil, is called from the background
+// go-routine with the number of dropped writes.
+func NewAsyncWriter(w io.Writer, size int, onDrop func(n int)) *AsyncWriter {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	aw := &AsyncWriter{
+		w: w,
+		waiter: NewWaiter[[]byte](
+			NewManyToOne[[]byte](size),
+			WithWaiterContext[[]byte](ctx),
+		),
+		onDrop: onDrop,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	aw.cond.L = &aw.mu
+
+	go aw.run()
+
+	return aw
+}
+
+// Write implements io.Writer. p is copied and written asynchronously, Write
+// never returns an error unless writer is closed.
+func (aw *AsyncWriter) Write(p []byte) (int, error) {
+	aw.closeMu.RLock()
+	defer aw.closeMu.RUnlock()
+
+	if aw.closed {
+		return 0, ErrClosed
+	}
+
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	aw.waiter.Push(data)
+	aw.written.Add(1)
+
+	return len(p), nil
+}
+
+// Flush waits until all writes done before the call are written to the
+// underlying writer or dropped. It returns the first error returned by the
+// underlying writer since last Flush.
+func (aw *AsyncWriter) Flush() error {
+	target := aw.written.Load()
+
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	for aw.processed < target {
+		aw.cond.Wait()
+	}
+
+	err := aw.err
+	aw.err = nil
+
+	return err
+}
+
+// Close flushes pending writes, stops the background go-routine and closes
+// the underlying writer if it implements io.Closer.
+func (aw *AsyncWriter) Close() error {
+	aw.closeMu.Lock()
+	closed := aw.closed
+	aw.closed = true
+	aw.closeMu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	err := aw.Flush()
+
+	aw.cancel()
+	<-aw.done
+
+	if closer, ok := aw.w.(io.Closer); ok {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+func (aw *AsyncWriter) run() {
+	defer close(aw.done)
+
+	for {
+		data, done, dropped := aw.waiter.Next()
+		if dropped > 0 && aw.onDrop != nil {
+			aw.onDrop(dropped)
+		}
+		if done {
+			return
+		}
+
+		_, err := aw.w.Write(data)
+
+		aw.mu.Lock()
+		aw.processed += uint64(1 + dropped)
+		if err != nil && aw.err == nil {
+			aw.err = err
+		}
+		aw.cond.Broadcast()
+		aw.mu.Unlock()
+	}
+}
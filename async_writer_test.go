@@ -0,0 +1,220 @@
+package ringo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowWriter is an io.WriteCloser that sleeps before each write.
+type slowWriter struct {
+	delay time.Duration
+	err   error
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+	closed bool
+}
+
+func (sw *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(sw.delay)
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.writes++
+	if sw.err != nil {
+		return 0, sw.err
+	}
+
+	return sw.buf.Write(p)
+}
+
+func (sw *slowWriter) Close() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	sw.closed = true
+	return nil
+}
+
+func (sw *slowWriter) String() string {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return sw.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	t.Run("WriteDoesNotBlock", func(t *testing.T) {
+		sw := &slowWriter{delay: 100 * time.Millisecond}
+		w := NewAsyncWriter(sw, 10, nil)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			n, err := w.Write([]byte("hello"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 5 {
+				t.Fatal("Write() returned wrong number of bytes:", n)
+			}
+		}
+		if time.Since(start) > 50*time.Millisecond {
+			t.Fatal("Write() blocked on underlying writer")
+		}
+
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if sw.String() != "hellohellohellohellohello" {
+			t.Fatal("data written to underlying writer doesn't match expected")
+		}
+	})
+
+	t.Run("WriteCopiesData", func(t *testing.T) {
+		sw := &slowWriter{delay: 10 * time.Millisecond}
+		w := NewAsyncWriter(sw, 10, nil)
+
+		p := []byte("hello")
+		_, _ = w.Write(p)
+		copy(p, "world")
+
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if sw.String() != "hello" {
+			t.Fatal("data written to underlying writer doesn't match expected")
+		}
+	})
+
+	t.Run("ReportDrops", func(t *testing.T) {
+		sw := &slowWriter{delay: 50 * time.Millisecond}
+		dropped := atomic.Int64{}
+		w := NewAsyncWriter(sw, 2, func(n int) {
+			dropped.Add(int64(n))
+		})
+
+		for i := 0; i < 100; i++ {
+			_, _ = w.Write([]byte(fmt.Sprint(i)))
+		}
+
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		sw.mu.Lock()
+		writes := sw.writes
+		sw.mu.Unlock()
+
+		if dropped.Load() == 0 {
+			t.Fatal("no drop reported")
+		}
+		if int(dropped.Load())+writes != 100 {
+			t.Fatalf("number of written and dropped values doesn't match expected, expected %v got %v", 100, int(dropped.Load())+writes)
+		}
+	})
+
+	t.Run("FlushReturnsWriteError", func(t *testing.T) {
+		expectedErr := errors.New("write error")
+		sw := &slowWriter{err: expectedErr}
+		w := NewAsyncWriter(sw, 10, nil)
+
+		_, _ = w.Write([]byte("hello"))
+		if err := w.Flush(); !errors.Is(err, expectedErr) {
+			t.Fatal("Flush() didn't return underlying writer error:", err)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal("Flush() returned an already reported error:", err)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		sw := &slowWriter{delay: 10 * time.Millisecond}
+		w := NewAsyncWriter(sw, 10, nil)
+
+		for i := 0; i < 5; i++ {
+			_, _ = w.Write([]byte("hello"))
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if sw.String() != "hellohellohellohellohello" {
+			t.Fatal("pending data wasn't flushed on Close()")
+		}
+		if !sw.closed {
+			t.Fatal("underlying writer wasn't closed")
+		}
+
+		if _, err := w.Write([]byte("hello")); !errors.Is(err, ErrClosed) {
+			t.Fatal("Write() on closed writer didn't return ErrClosed:", err)
+		}
+		if err := w.Close(); !errors.Is(err, ErrClosed) {
+			t.Fatal("second Close() didn't return ErrClosed:", err)
+		}
+	})
+
+	t.Run("MultipleWriter", func(t *testing.T) {
+		sw := &slowWriter{}
+		w := NewAsyncWriter(sw, 1000, nil)
+
+		var wg sync.WaitGroup
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, _ = w.Write([]byte("x"))
+				}
+			}()
+		}
+		wg.Wait()
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if len(sw.String()) != 1000 {
+			t.Fatal("data written to underlying writer doesn't match expected")
+		}
+	})
+
+	t.Run("WriteDuringClose", func(t *testing.T) {
+		sw := &slowWriter{}
+		w := NewAsyncWriter(sw, 10000, nil)
+
+		var accepted atomic.Int64
+		var wg sync.WaitGroup
+		wg.Add(10)
+		for i := 0; i < 10; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					if _, err := w.Write([]byte("x")); err != nil {
+						return
+					}
+					accepted.Add(1)
+				}
+			}()
+		}
+
+		for accepted.Load() == 0 {
+			runtime.Gosched()
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		wg.Wait()
+
+		// Every accepted write must be flushed before Close returns.
+		if n := int64(len(sw.String())); n != accepted.Load() {
+			t.Fatalf("%v bytes written to underlying writer, expecting %v", n, accepted.Load())
+		}
+	})
+}
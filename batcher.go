@@ -0,0 +1,181 @@
+package ringo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Batcher reads values of a Buffer in a background go-routine, accumulates
+// them and flushes them in batches once a batch is full or its oldest value
+// waited for the maximum latency. Batcher polls the buffer when it is empty,
+// even if it is wrapped by a Waiter. Latency is measured from the time a value
+// is read, so it may have waited up to a polling interval more in the buffer.
+type Batcher[T any] struct {
+	buffer     Buffer[T]
+	flush      func(batch []T, dropped int)
+	size       int
+	maxLatency time.Duration
+	interval   time.Duration
+
+	ctx       context.Context
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	batch []T
+	// Values dropped since last flush.
+	pending int
+	dropped atomic.Uint64
+}
+
+// BatcherOption can be used to setup the Batcher.
+type BatcherOption[T any] func(*Batcher[T])
+
+// WithBatcherContext sets context of the batcher. Once it is done, current
+// batch is flushed and batcher stops without draining the buffer. Default is
+// context.Background().
+func WithBatcherContext[T any](ctx context.Context) BatcherOption[T] {
+	return func(b *Batcher[T]) {
+		b.ctx = ctx
+	}
+}
+
+// WithBatcherPollingInterval sets the interval at which an empty buffer is
+// queried for new data. The default is 10ms or max latency if it is lower.
+func WithBatcherPollingInterval[T any](interval time.Duration) BatcherOption[T] {
+	return func(b *Batcher[T]) {
+		b.interval = interval
+	}
+}
+
+// NewBatcher returns a new Batcher reading values of buffer and calling flush
+// with batches of at most size values, at most maxLatency after first value of
+// the batch was read. Dropped is the number of values dropped by buffer since
+// previous flush. Batch slice is reused, flush must not retain it.
+func NewBatcher[T any](buffer Buffer[T], size int, maxLatency time.Duration, flush func(batch []T, dropped int), options ...BatcherOption[T]) *Batcher[T] {
+	if size <= 0 {
+		panic("batch size can't be negative or zero")
+	}
+	if maxLatency <= 0 {
+		panic("batch max latency can't be negative or zero")
+	}
+
+	interval := 10 * time.Millisecond
+	if maxLatency < interval {
+		interval = maxLatency
+	}
+
+	b := &Batcher[T]{
+		buffer:     buffer,
+		flush:      flush,
+		size:       size,
+		maxLatency: maxLatency,
+		interval:   interval,
+		ctx:        context.Background(),
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+		batch:      make([]T, 0, size),
+	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	go b.run()
+
+	return b
+}
+
+// Close stops the batcher gracefully: values remaining in the buffer are read
+// and flushed before Close returns. Producers should be stopped before.
+func (b *Batcher[T]) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closing)
+	})
+	<-b.done
+}
+
+// Done returns a channel closed once batcher stopped.
+func (b *Batcher[T]) Done() <-chan struct{} {
+	return b.done
+}
+
+// Dropped returns total number of values dropped by buffer.
+func (b *Batcher[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	// Flush remaining values and drops on shutdown.
+	defer b.doFlush()
+
+	timer := time.NewTimer(b.interval)
+	defer timer.Stop()
+
+	var deadline time.Time
+	for {
+		next, ok, dropped := b.buffer.TryNext()
+		if dropped > 0 {
+			b.pending += dropped
+			b.dropped.Add(uint64(dropped))
+		}
+
+		if ok {
+			if len(b.batch) == 0 {
+				deadline = time.Now().Add(b.maxLatency)
+			}
+			b.batch = append(b.batch, next)
+			if len(b.batch) == b.size {
+				b.doFlush()
+			}
+			continue
+		}
+
+		// Buffer is drained.
+		select {
+		case <-b.closing:
+			return
+		default:
+		}
+
+		wait := b.interval
+		if len(b.batch) > 0 {
+			until := time.Until(deadline)
+			if until <= 0 {
+				b.doFlush()
+				continue
+			}
+			if until < wait {
+				wait = until
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-b.closing:
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *Batcher[T]) doFlush() {
+	if len(b.batch) == 0 && b.pending == 0 {
+		return
+	}
+
+	b.flush(b.batch, b.pending)
+	b.batch = b.batch[:0]
+	b.pending = 0
+}
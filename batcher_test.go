@@ -0,0 +1,179 @@
+package ringo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecorder records flushed batches.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	dropped []int
+	flushed chan struct{}
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{flushed: make(chan struct{}, 100)}
+}
+
+func (br *batchRecorder) flush(batch []int, dropped int) {
+	br.mu.Lock()
+	br.batches = append(br.batches, append([]int{}, batch...))
+	br.dropped = append(br.dropped, dropped)
+	br.mu.Unlock()
+
+	br.flushed <- struct{}{}
+}
+
+func (br *batchRecorder) get() ([][]int, []int) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	return br.batches, br.dropped
+}
+
+func TestBatcher(t *testing.T) {
+	t.Run("SizeTrigger", func(t *testing.T) {
+		buffer := NewManyToOne[int](1000)
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i)
+		}
+
+		recorder := newBatchRecorder()
+		batcher := NewBatcher[int](buffer, 100, time.Hour, recorder.flush)
+		batcher.Close()
+
+		batches, dropped := recorder.get()
+		if len(batches) != 10 {
+			t.Fatalf("%v batches flushed, expecting 10", len(batches))
+		}
+		for i, batch := range batches {
+			if len(batch) != 100 || dropped[i] != 0 {
+				t.Fatalf("batch %v has %v values and %v dropped", i, len(batch), dropped[i])
+			}
+			for j, v := range batch {
+				if v != i*100+j {
+					t.Fatal("batch value doesn't match expected:", v)
+				}
+			}
+		}
+	})
+
+	t.Run("LatencyTrigger", func(t *testing.T) {
+		buffer := NewManyToOne[int](1000)
+		recorder := newBatchRecorder()
+		batcher := NewBatcher[int](buffer, 100, 20*time.Millisecond, recorder.flush)
+		defer batcher.Close()
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			buffer.Push(i)
+		}
+
+		select {
+		case <-recorder.flushed:
+		case <-time.After(time.Second):
+			t.Fatal("batch wasn't flushed after max latency")
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Fatal("batch flushed before max latency:", elapsed)
+		}
+
+		batches, _ := recorder.get()
+		if len(batches[0]) != 5 {
+			t.Fatalf("batch has %v values, expecting 5", len(batches[0]))
+		}
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		buffer := NewManyToOne[int](10)
+		for i := 0; i < 100; i++ {
+			buffer.Push(i)
+		}
+
+		recorder := newBatchRecorder()
+		batcher := NewBatcher[int](buffer, 5, time.Hour, recorder.flush)
+		batcher.Close()
+
+		batches, dropped := recorder.get()
+		if len(batches) != 2 {
+			t.Fatalf("%v batches flushed, expecting 2", len(batches))
+		}
+		if batches[0][0] != 90 || dropped[0] != 90 || dropped[1] != 0 {
+			t.Fatal("dropped values weren't reported with batch:", dropped)
+		}
+		if batcher.Dropped() != 90 {
+			t.Fatal("Dropped() returned", batcher.Dropped())
+		}
+	})
+
+	t.Run("FlushRemainingOnClose", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		recorder := newBatchRecorder()
+		batcher := NewBatcher[int](buffer, 100, time.Hour, recorder.flush)
+
+		for i := 0; i < 7; i++ {
+			buffer.Push(i)
+		}
+		batcher.Close()
+
+		batches, _ := recorder.get()
+		if len(batches) != 1 || len(batches[0]) != 7 {
+			t.Fatal("remaining values weren't flushed on close:", batches)
+		}
+	})
+
+	t.Run("ContextCancellation", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		recorder := newBatchRecorder()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		batcher := NewBatcher[int](buffer, 100, time.Hour, recorder.flush,
+			WithBatcherContext[int](ctx), WithBatcherPollingInterval[int](time.Millisecond))
+
+		buffer.Push(1)
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-batcher.Done():
+		case <-time.After(time.Second):
+			t.Fatal("batcher didn't stop on context cancellation")
+		}
+
+		batches, _ := recorder.get()
+		if len(batches) != 1 || len(batches[0]) != 1 {
+			t.Fatal("current batch wasn't flushed on context cancellation:", batches)
+		}
+	})
+
+	t.Run("InvalidMaxLatency", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("NewBatcher() didn't panic on zero max latency")
+			}
+		}()
+
+		NewBatcher[int](NewManyToOne[int](100), 100, 0, func([]int, int) {})
+	})
+
+	t.Run("ReuseSlice", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		for i := 0; i < 10; i++ {
+			buffer.Push(i)
+		}
+
+		arrays := map[*int]bool{}
+		batcher := NewBatcher[int](buffer, 2, time.Hour, func(batch []int, _ int) {
+			arrays[&batch[:1][0]] = true
+		})
+		batcher.Close()
+
+		if len(arrays) != 1 {
+			t.Fatal("batch slice wasn't reused")
+		}
+	})
+}
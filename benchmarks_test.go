@@ -1,6 +1,8 @@
 package ringo
 
 import (
+	"context"
+	"runtime"
 	"sync"
 	"testing"
 )
@@ -107,3 +109,122 @@ func BenchmarkManyToOnePoller(b *testing.B) {
 		buffer.Next()
 	}
 }
+
+type pipelineEvent struct {
+	value    int
+	decoded  int
+	enriched int
+}
+
+func BenchmarkDisruptorPipeline(b *testing.B) {
+	d := NewDisruptor[pipelineEvent](1024)
+	ctx := context.Background()
+
+	decode := d.NewConsumer(d.Barrier())
+	enrich := d.NewConsumer(d.Barrier(decode))
+	persist := d.NewConsumer(d.Barrier(enrich))
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	runStage := func(c *DisruptorConsumer[pipelineEvent], fn func(ev *pipelineEvent)) {
+		defer wg.Done()
+		for c.Sequence() < uint64(b.N) {
+			_ = c.Process(ctx, func(_ uint64, ev *pipelineEvent) {
+				fn(ev)
+			})
+		}
+	}
+
+	b.ResetTimer()
+
+	go runStage(decode, func(ev *pipelineEvent) { ev.decoded = ev.value })
+	go runStage(enrich, func(ev *pipelineEvent) { ev.enriched = ev.decoded })
+	go runStage(persist, func(ev *pipelineEvent) {
+		if ev.enriched != ev.value {
+			panic("invalid event")
+		}
+	})
+
+	for i := 0; i < b.N; i++ {
+		seq, ev, _ := d.Claim(ctx)
+		ev.value = i
+		d.Publish(seq)
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkManyToOnePipeline(b *testing.B) {
+	decoded := NewManyToOne[pipelineEvent](max(b.N, 1))
+	enriched := NewManyToOne[pipelineEvent](max(b.N, 1))
+	persisted := NewManyToOne[pipelineEvent](max(b.N, 1))
+	input := NewManyToOne[pipelineEvent](max(b.N, 1))
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	runStage := func(in, out Buffer[pipelineEvent], fn func(ev *pipelineEvent)) {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			ev, ok, _ := in.TryNext()
+			if !ok {
+				runtime.Gosched()
+				continue
+			}
+			fn(&ev)
+			out.Push(ev)
+			i++
+		}
+	}
+
+	b.ResetTimer()
+
+	go runStage(input, decoded, func(ev *pipelineEvent) { ev.decoded = ev.value })
+	go runStage(decoded, enriched, func(ev *pipelineEvent) { ev.enriched = ev.decoded })
+	go runStage(enriched, persisted, func(ev *pipelineEvent) {
+		if ev.enriched != ev.value {
+			panic("invalid event")
+		}
+	})
+
+	for i := 0; i < b.N; i++ {
+		input.Push(pipelineEvent{value: i})
+	}
+
+	wg.Wait()
+}
+
+func benchmarkParallelPush(b *testing.B, buffer Buffer[int]) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < b.N; {
+			_, ok, dropped := buffer.TryNext()
+			if ok {
+				i++
+			}
+			i += dropped
+			if !ok {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			buffer.Push(i)
+		}
+	})
+
+	<-done
+}
+
+// Run with -cpu=1,2,4,8 to compare scaling.
+func BenchmarkManyToOneParallel(b *testing.B) {
+	benchmarkParallelPush(b, NewManyToOne[int](max(b.N, 1)))
+}
+
+// Run with -cpu=1,2,4,8 to compare scaling.
+func BenchmarkShardedParallel(b *testing.B) {
+	benchmarkParallelPush(b, NewSharded[int](max(b.N, 1)))
+}
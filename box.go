@@ -1,7 +1,45 @@
 package ringo
 
+import "sync/atomic"
+
 // Box wraps a T to store its index within a ring buffer.
 type box[T any] struct {
 	index uint64
 	data  T
 }
+
+// seqBefore reports whether sequence number a precedes b. Sequence numbers
+// wrap, so their distance is compared instead.
+func seqBefore(a, b uint64) bool {
+	return int64(a-b) < 0
+}
+
+// pushBox pushes data to a ring of boxes safe for use by concurrent writers.
+// Writer claims a sequence number and stores its box unless slot holds a newer
+// one: writer was lapped and claims a new sequence number. A CompareAndSwap
+// lost against an older box is retried with the same sequence number, giving
+// it up would leave a hole where reader stalls.
+func pushBox[T any](buffer []atomic.Pointer[box[T]], writeIndex *atomic.Uint64, data T, onCollision func()) {
+	for {
+		schedYield(schedPushClaim)
+		seq := writeIndex.Add(1)
+		slot := &buffer[seq%uint64(len(buffer))]
+		b := &box[T]{index: seq, data: data}
+
+		for {
+			schedYield(schedPushLoad)
+			old := slot.Load()
+			if old != nil && seqBefore(seq, old.index) {
+				break
+			}
+
+			schedYield(schedPushSwap)
+			if slot.CompareAndSwap(old, b) {
+				return
+			}
+			onCollision()
+		}
+
+		onCollision()
+	}
+}
@@ -0,0 +1,40 @@
+package ringo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/negrel/ringo"
+	"github.com/negrel/ringo/ringotest"
+)
+
+func TestBufferSuite(t *testing.T) {
+	t.Run("Ring", func(t *testing.T) {
+		ringotest.RunBufferSuite(t, func(size int) ringo.Buffer[int] {
+			return ringo.NewRing[int](size)
+		})
+	})
+
+	t.Run("ManyToOne", func(t *testing.T) {
+		ringotest.RunBufferSuite(t, func(size int) ringo.Buffer[int] {
+			return ringo.NewManyToOne[int](size,
+				ringo.WithManyToOneCollisionHandler[int](ringo.CollisionHandlerFunc(func(any) {})),
+			)
+		}, ringotest.WithConcurrentWriters(100), ringotest.WithWastedSequences())
+	})
+
+	t.Run("ConsumerGroup", func(t *testing.T) {
+		ringotest.RunBufferSuite(t, func(size int) ringo.Buffer[int] {
+			ring := ringo.NewGroupRing[int](size,
+				ringo.WithGroupRingCollisionHandler[int](ringo.CollisionHandlerFunc(func(any) {})),
+			)
+			return ring.Group("suite")
+		}, ringotest.WithConcurrentWriters(100), ringotest.WithWastedSequences())
+	})
+
+	t.Run("TimeRing", func(t *testing.T) {
+		ringotest.RunBufferSuite(t, func(size int) ringo.Buffer[int] {
+			return ringo.NewTimeRing[int](size, time.Hour)
+		})
+	})
+}
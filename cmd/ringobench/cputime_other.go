@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import "time"
+
+// cpuTime isn't supported on this platform.
+func cpuTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,100 @@
+// Command ringobench runs end-to-end benchmarks of ringo buffers against a
+// buffered channel baseline. Producers and consumer run concurrently during a
+// fixed duration, ringobench then reports throughput, end-to-end latency
+// percentiles, drop rate and CPU time.
+//
+// Usage:
+//
+//	ringobench -producers 8 -consumer waiter -buffers manytoone,chan -duration 10s
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	var (
+		producers    = flag.Int("producers", runtime.GOMAXPROCS(0), "number of producer go-routines")
+		consumer     = flag.String("consumer", "spin", "consumer type of ringo buffers: spin, poller or waiter")
+		buffers      = flag.String("buffers", "manytoone,sharded,chan", "comma separated list of buffers: manytoone, sharded or chan")
+		size         = flag.Int("size", 1024, "buffer size")
+		payload      = flag.Int("payload", 64, "payload size in bytes")
+		duration     = flag.Duration("duration", 5*time.Second, "duration of each scenario")
+		pollInterval = flag.Duration("poll-interval", time.Millisecond, "polling interval of poller consumer")
+		sample       = flag.Int("sample", 16, "record latency of one message every sample messages")
+		format       = flag.String("format", "text", "output format: text or json")
+	)
+	flag.Parse()
+
+	if *producers <= 0 || *size <= 0 || *payload < 0 || *sample <= 0 || *duration <= 0 {
+		fmt.Fprintln(os.Stderr, "ringobench: producers, size, sample and duration must be positive")
+		os.Exit(2)
+	}
+
+	results := []result{}
+	for _, buffer := range strings.Split(*buffers, ",") {
+		s := scenario{
+			Buffer:       strings.TrimSpace(buffer),
+			Consumer:     *consumer,
+			Producers:    *producers,
+			Size:         *size,
+			Payload:      *payload,
+			Duration:     *duration,
+			PollInterval: *pollInterval,
+			Sample:       *sample,
+		}
+		if s.Buffer == "chan" {
+			s.Consumer = "chan"
+		}
+
+		r, err := run(s)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ringobench:", err)
+			os.Exit(1)
+		}
+		results = append(results, r)
+	}
+
+	var err error
+	switch *format {
+	case "text":
+		err = writeText(os.Stdout, results)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ringobench:", err)
+		os.Exit(1)
+	}
+}
+
+func writeText(w io.Writer, results []result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "buffer\tconsumer\tproducers\tmsg/s\tdrop rate\tp50\tp90\tp99\tp99.9\tmax\tcpu time\t")
+	for _, r := range results {
+		cpu := "n/a"
+		if r.CPUTime > 0 {
+			cpu = r.CPUTime.Round(time.Millisecond).String()
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%.0f\t%.2f%%\t%v\t%v\t%v\t%v\t%v\t%v\t\n",
+			r.Scenario.Buffer, r.Scenario.Consumer, r.Scenario.Producers,
+			r.Throughput, 100*r.DropRate,
+			r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.P999, r.Latency.Max,
+			cpu)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/negrel/ringo"
+	"github.com/negrel/ringo/stats"
+)
+
+// message is sent from producers to consumer.
+type message struct {
+	// Time elapsed since start of scenario when message was sent.
+	sent    time.Duration
+	payload []byte
+}
+
+// scenario define a benchmark configuration.
+type scenario struct {
+	Buffer       string        `json:"buffer"`
+	Consumer     string        `json:"consumer"`
+	Producers    int           `json:"producers"`
+	Size         int           `json:"size"`
+	Payload      int           `json:"payload"`
+	Duration     time.Duration `json:"duration"`
+	PollInterval time.Duration `json:"poll_interval"`
+	// Latency of one message every Sample messages is recorded.
+	Sample int `json:"sample"`
+}
+
+// latency define end-to-end latency percentiles.
+type latency struct {
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	P999 time.Duration `json:"p999"`
+	Max  time.Duration `json:"max"`
+}
+
+// result define result of a scenario.
+type result struct {
+	Scenario   scenario `json:"scenario"`
+	Sent       uint64   `json:"sent"`
+	Received   uint64   `json:"received"`
+	Dropped    uint64   `json:"dropped"`
+	DropRate   float64  `json:"drop_rate"`
+	Throughput float64  `json:"throughput"`
+	Latency    latency  `json:"latency"`
+	// CPU time (user + system) of the process, zero if unavailable.
+	CPUTime time.Duration `json:"cpu_time"`
+}
+
+// queue abstracts buffers under benchmark.
+type queue interface {
+	// push sends a message and returns true if it was dropped.
+	push(msg message) (dropped bool)
+	// next waits for next message. Returned boolean is false once context is
+	// done and queue is empty.
+	next() (msg message, ok bool, dropped int)
+}
+
+// noCollisionHandler silences collision warnings during benchmarks.
+var noCollisionHandler = ringo.CollisionHandlerFunc(func(any) {})
+
+func newQueue(ctx context.Context, s scenario) (queue, error) {
+	var buffer ringo.Buffer[message]
+	switch s.Buffer {
+	case "manytoone":
+		buffer = ringo.NewManyToOne[message](s.Size,
+			ringo.WithManyToOneCollisionHandler[message](noCollisionHandler))
+	case "sharded":
+		buffer = ringo.NewSharded[message](s.Size, ringo.WithShardOptions[message](
+			ringo.WithManyToOneCollisionHandler[message](noCollisionHandler)))
+	case "chan":
+		return &chanQueue{ctx: ctx, c: make(chan message, s.Size)}, nil
+	default:
+		return nil, fmt.Errorf("unknown buffer %q", s.Buffer)
+	}
+
+	switch s.Consumer {
+	case "spin":
+		return &spinQueue{ctx: ctx, buffer: buffer}, nil
+	case "poller":
+		return &pollerQueue{
+			buffer: buffer,
+			poller: ringo.NewPoller(buffer,
+				ringo.WithPollingContext[message](ctx),
+				ringo.WithPollingInterval[message](s.PollInterval)),
+		}, nil
+	case "waiter":
+		return &waiterQueue{
+			buffer: buffer,
+			waiter: ringo.NewWaiter(buffer, ringo.WithWaiterContext[message](ctx)),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown consumer %q", s.Consumer)
+	}
+}
+
+// spinQueue reads buffer in a busy loop.
+type spinQueue struct {
+	ctx    context.Context
+	buffer ringo.Buffer[message]
+}
+
+func (sq *spinQueue) push(msg message) bool {
+	sq.buffer.Push(msg)
+	return false
+}
+
+func (sq *spinQueue) next() (message, bool, int) {
+	for {
+		msg, ok, dropped := sq.buffer.TryNext()
+		if ok || sq.ctx.Err() != nil {
+			return msg, ok, dropped
+		}
+		runtime.Gosched()
+	}
+}
+
+// pollerQueue reads buffer using a ringo.Poller.
+type pollerQueue struct {
+	buffer ringo.Buffer[message]
+	poller ringo.Poller[message]
+}
+
+func (pq *pollerQueue) push(msg message) bool {
+	pq.buffer.Push(msg)
+	return false
+}
+
+func (pq *pollerQueue) next() (message, bool, int) {
+	msg, done, dropped := pq.poller.Next()
+	if done {
+		return pq.buffer.TryNext()
+	}
+
+	return msg, true, dropped
+}
+
+// waiterQueue reads buffer using a ringo.Waiter, producers push through it.
+type waiterQueue struct {
+	buffer ringo.Buffer[message]
+	waiter ringo.Waiter[message]
+}
+
+func (wq *waiterQueue) push(msg message) bool {
+	wq.waiter.Push(msg)
+	return false
+}
+
+func (wq *waiterQueue) next() (message, bool, int) {
+	msg, done, dropped := wq.waiter.Next()
+	if done {
+		return wq.buffer.TryNext()
+	}
+
+	return msg, true, dropped
+}
+
+// chanQueue is a buffered channel baseline. Messages are dropped when channel
+// is full, like in ringo buffers, but newest are dropped instead of oldest.
+type chanQueue struct {
+	ctx context.Context
+	c   chan message
+}
+
+func (cq *chanQueue) push(msg message) bool {
+	select {
+	case cq.c <- msg:
+		return false
+	default:
+		return true
+	}
+}
+
+func (cq *chanQueue) next() (message, bool, int) {
+	select {
+	case msg := <-cq.c:
+		return msg, true, 0
+	case <-cq.ctx.Done():
+		select {
+		case msg := <-cq.c:
+			return msg, true, 0
+		default:
+			return message{}, false, 0
+		}
+	}
+}
+
+// run runs scenario and returns its result.
+func run(s scenario) (result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Duration)
+	defer cancel()
+
+	q, err := newQueue(ctx, s)
+	if err != nil {
+		return result{}, err
+	}
+
+	// Only consumer go-routine records latencies.
+	latencies := stats.NewHistogram(0.01)
+	var sent, chanDropped atomic.Uint64
+
+	cpuStart, _ := cpuTime()
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	wg.Add(s.Producers)
+	for i := 0; i < s.Producers; i++ {
+		go func() {
+			defer wg.Done()
+
+			count, dropped := uint64(0), uint64(0)
+			for ctx.Err() == nil {
+				msg := message{
+					sent:    time.Since(start),
+					payload: make([]byte, s.Payload),
+				}
+				if q.push(msg) {
+					dropped++
+				}
+				count++
+			}
+
+			sent.Add(count)
+			chanDropped.Add(dropped)
+		}()
+	}
+
+	received, dropped := uint64(0), uint64(0)
+	for {
+		msg, ok, d := q.next()
+		dropped += uint64(d)
+		if !ok {
+			// Producers may still be pushing after context is done.
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if received%uint64(s.Sample) == 0 {
+			latencies.Add(float64(time.Since(start) - msg.sent))
+		}
+		received++
+	}
+	wg.Wait()
+	// Drain values pushed after consumer stopped.
+	for {
+		_, ok, d := q.next()
+		dropped += uint64(d)
+		if !ok {
+			break
+		}
+		received++
+	}
+
+	elapsed := time.Since(start)
+	cpuEnd, ok := cpuTime()
+
+	r := result{
+		Scenario:   s,
+		Sent:       sent.Load(),
+		Received:   received,
+		Dropped:    dropped + chanDropped.Load(),
+		Throughput: float64(received) / elapsed.Seconds(),
+		Latency: latency{
+			P50:  time.Duration(latencies.Quantile(0.5)),
+			P90:  time.Duration(latencies.Quantile(0.9)),
+			P99:  time.Duration(latencies.Quantile(0.99)),
+			P999: time.Duration(latencies.Quantile(0.999)),
+			Max:  time.Duration(latencies.Max()),
+		},
+	}
+	if r.Sent > 0 {
+		r.DropRate = float64(r.Dropped) / float64(r.Sent)
+	}
+	if ok {
+		r.CPUTime = cpuEnd - cpuStart
+	}
+
+	return r, nil
+}
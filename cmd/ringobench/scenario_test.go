@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	for _, buffer := range []string{"manytoone", "sharded", "chan"} {
+		for _, consumer := range []string{"spin", "poller", "waiter"} {
+			t.Run(buffer+"/"+consumer, func(t *testing.T) {
+				r, err := run(scenario{
+					Buffer:       buffer,
+					Consumer:     consumer,
+					Producers:    2,
+					Size:         64,
+					Payload:      8,
+					Duration:     20 * time.Millisecond,
+					PollInterval: time.Millisecond,
+					Sample:       1,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if r.Received == 0 || r.Received > r.Sent {
+					t.Fatalf("received %v of %v sent messages", r.Received, r.Sent)
+				}
+				if r.Received+r.Dropped > r.Sent+uint64(r.Scenario.Size) {
+					t.Fatalf("received and dropped %v messages, only %v were sent", r.Received+r.Dropped, r.Sent)
+				}
+				if r.Latency.Max <= 0 {
+					t.Fatal("latency not recorded")
+				}
+			})
+		}
+	}
+
+	t.Run("UnknownBuffer", func(t *testing.T) {
+		_, err := run(scenario{Buffer: "unknown", Consumer: "spin", Duration: time.Millisecond})
+		if err == nil {
+			t.Fatal("run() didn't fail")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package ringo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec define an encoder and a decoder of T values. It is used by ring
+// buffers storing serialized data.
+type Codec[T any] interface {
+	// Encode appends encoded v to dst and returns the extended buffer.
+	Encode(dst []byte, v T) ([]byte, error)
+	// Decode decodes a value previously encoded by Encode. Decode must not
+	// retain data.
+	Decode(data []byte) (T, error)
+}
+
+var (
+	_ Codec[[]byte] = BytesCodec{}
+	_ Codec[string] = StringCodec{}
+	_ Codec[any]    = BinaryCodec[any]{}
+	_ Codec[any]    = JSONCodec[any]{}
+	_ Codec[any]    = GobCodec[any]{}
+)
+
+// BytesCodec is a Codec of []byte stored as is. Decode returns a copy of
+// data.
+type BytesCodec struct{}
+
+// Encode implements Codec.
+func (BytesCodec) Encode(dst []byte, v []byte) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+// Decode implements Codec.
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return append([]byte(nil), data...), nil
+}
+
+// StringCodec is a Codec of string stored as is.
+type StringCodec struct{}
+
+// Encode implements Codec.
+func (StringCodec) Encode(dst []byte, v string) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+// Decode implements Codec.
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// BinaryCodec is a Codec of fixed-size values (see encoding/binary) such as
+// numbers, arrays and structs of fixed-size values. ByteOrder defaults to
+// binary.LittleEndian.
+type BinaryCodec[T any] struct {
+	ByteOrder binary.ByteOrder
+}
+
+func (bc BinaryCodec[T]) byteOrder() binary.ByteOrder {
+	if bc.ByteOrder == nil {
+		return binary.LittleEndian
+	}
+
+	return bc.ByteOrder
+}
+
+// Encode implements Codec.
+func (bc BinaryCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	err := binary.Write(buf, bc.byteOrder(), v)
+	if err != nil {
+		return dst, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (bc BinaryCodec[T]) Decode(data []byte) (v T, err error) {
+	if size := binary.Size(v); size != len(data) {
+		return v, fmt.Errorf("ringo: binary codec expected %v bytes, got %v", size, len(data))
+	}
+
+	err = binary.Read(bytes.NewReader(data), bc.byteOrder(), &v)
+	return v, err
+}
+
+// JSONCodec is a Codec of values encoded using encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode implements Codec.
+func (JSONCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+
+	return append(dst, data...), nil
+}
+
+// Decode implements Codec.
+func (JSONCodec[T]) Decode(data []byte) (v T, err error) {
+	err = json.Unmarshal(data, &v)
+	return v, err
+}
+
+// GobCodec is a Codec of values encoded using encoding/gob. Each value is
+// encoded with its type information, so it can be decoded independently of
+// other values.
+type GobCodec[T any] struct{}
+
+// Encode implements Codec.
+func (GobCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	err := gob.NewEncoder(buf).Encode(v)
+	if err != nil {
+		return dst, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (GobCodec[T]) Decode(data []byte) (v T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+	return v, err
+}
@@ -0,0 +1,109 @@
+package ringo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+type codecTestStruct struct {
+	ID    uint32
+	Value float64
+	Tags  [4]byte
+}
+
+func testCodecRoundTrip[T any](t *testing.T, codec Codec[T], v T) {
+	t.Helper()
+
+	prefix := []byte("prefix")
+	encoded, err := codec.Encode(append([]byte(nil), prefix...), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(encoded, prefix) {
+		t.Fatal("Encode() didn't append to dst")
+	}
+
+	decoded, err := codec.Decode(encoded[len(prefix):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, v) {
+		t.Fatalf("decoded value doesn't match expected, expected %v got %v", v, decoded)
+	}
+}
+
+func TestCodec(t *testing.T) {
+	t.Run("Bytes", func(t *testing.T) {
+		testCodecRoundTrip[[]byte](t, BytesCodec{}, []byte("hello world"))
+
+		data := []byte("hello")
+		decoded, _ := BytesCodec{}.Decode(data)
+		data[0] = 'j'
+		if string(decoded) != "hello" {
+			t.Fatal("Decode() didn't copy data")
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		testCodecRoundTrip[string](t, StringCodec{}, "hello world")
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		testCodecRoundTrip[int64](t, BinaryCodec[int64]{}, -42)
+		testCodecRoundTrip[codecTestStruct](t, BinaryCodec[codecTestStruct]{}, codecTestStruct{
+			ID:    1,
+			Value: 3.14,
+			Tags:  [4]byte{1, 2, 3, 4},
+		})
+		testCodecRoundTrip[uint32](t, BinaryCodec[uint32]{ByteOrder: binary.BigEndian}, 42)
+
+		encoded, _ := BinaryCodec[uint32]{ByteOrder: binary.BigEndian}.Encode(nil, 1)
+		if !bytes.Equal(encoded, []byte{0, 0, 0, 1}) {
+			t.Fatal("Encode() didn't use given byte order")
+		}
+
+		_, err := BinaryCodec[uint32]{}.Decode([]byte{1, 2})
+		if err == nil {
+			t.Fatal("Decode() of truncated data didn't fail")
+		}
+
+		_, err = BinaryCodec[string]{}.Encode(nil, "variable size")
+		if err == nil {
+			t.Fatal("Encode() of variable size value didn't fail")
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		testCodecRoundTrip[map[string]int](t, JSONCodec[map[string]int]{}, map[string]int{"a": 1, "b": 2})
+
+		_, err := JSONCodec[int]{}.Decode([]byte("{"))
+		if err == nil {
+			t.Fatal("Decode() of invalid data didn't fail")
+		}
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		testCodecRoundTrip[codecTestStruct](t, GobCodec[codecTestStruct]{}, codecTestStruct{
+			ID:    1,
+			Value: 3.14,
+			Tags:  [4]byte{1, 2, 3, 4},
+		})
+		testCodecRoundTrip[[]string](t, GobCodec[[]string]{}, []string{"a", "b"})
+	})
+
+	t.Run("FileRing", func(t *testing.T) {
+		fr, err := OpenFileRing[string](t.TempDir()+"/ring", 10, 64, JSONCodec[string]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fr.Close()
+
+		fr.Push("hello")
+		next, ok, _ := fr.TryNext()
+		if !ok || next != "hello" {
+			t.Fatal("value read from buffer doesn't match expected")
+		}
+	})
+}
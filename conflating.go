@@ -0,0 +1,84 @@
+package ringo
+
+import "sync"
+
+// Conflating define a buffer that keeps only the latest value of each key.
+// Pushing a key that is already pending replaces its value in place instead of
+// queuing a duplicate. Reader gets each pending key once, in first pushed
+// order. Memory usage is bounded by the number of distinct keys.
+//
+// Conflating is safe for use by concurrent writers and a single reader.
+type Conflating[K comparable, T any] struct {
+	mu      sync.Mutex
+	pending map[K]T
+	// Pending keys in first pushed order, starting at head.
+	keys []K
+	head int
+	// Number of values replaced since last read.
+	conflated int
+}
+
+// NewConflating returns a new empty Conflating buffer.
+func NewConflating[K comparable, T any]() *Conflating[K, T] {
+	return &Conflating[K, T]{
+		pending: make(map[K]T),
+	}
+}
+
+// Len returns number of pending keys.
+func (c *Conflating[K, T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pending)
+}
+
+// Push data of the given key to buffer. If key is already pending, its value
+// is replaced.
+func (c *Conflating[K, T]) Push(key K, data T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[key]; ok {
+		c.conflated++
+	} else {
+		c.keys = append(c.keys, key)
+	}
+
+	c.pending[key] = data
+}
+
+// TryNext reads oldest pending key and its latest value. Returned boolean is
+// true if a value was successfully read. Int correspond to the number of
+// values replaced (conflated) since last read.
+func (c *Conflating[K, T]) TryNext() (key K, result T, ok bool, conflated int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.head == len(c.keys) {
+		return
+	}
+
+	key = c.keys[c.head]
+	var zeroK K
+	c.keys[c.head] = zeroK
+	c.head++
+
+	// Reuse or compact keys slice.
+	if c.head == len(c.keys) {
+		c.keys = c.keys[:0]
+		c.head = 0
+	} else if c.head > 64 && c.head > len(c.keys)/2 {
+		n := copy(c.keys, c.keys[c.head:])
+		c.keys = c.keys[:n]
+		c.head = 0
+	}
+
+	result = c.pending[key]
+	delete(c.pending, key)
+
+	conflated = c.conflated
+	c.conflated = 0
+
+	return key, result, true, conflated
+}
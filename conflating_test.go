@@ -0,0 +1,117 @@
+package ringo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConflating(t *testing.T) {
+	t.Run("LatestValuePerKey", func(t *testing.T) {
+		buffer := NewConflating[string, float64]()
+
+		buffer.Push("EURUSD", 1.08)
+		buffer.Push("BTCUSD", 60000)
+		buffer.Push("EURUSD", 1.09)
+		buffer.Push("EURUSD", 1.10)
+
+		if buffer.Len() != 2 {
+			t.Fatal("number of pending keys doesn't match expected:", buffer.Len())
+		}
+
+		key, next, ok, conflated := buffer.TryNext()
+		if !ok {
+			t.Fatal("TryNext() returned false, expecting true")
+		}
+		if key != "EURUSD" || next != 1.10 {
+			t.Fatal("value read from buffer doesn't match expected:", key, next)
+		}
+		if conflated != 2 {
+			t.Fatal("buffer reported wrong number of conflated value:", conflated)
+		}
+
+		key, next, ok, conflated = buffer.TryNext()
+		if !ok || key != "BTCUSD" || next != 60000 || conflated != 0 {
+			t.Fatal("value read from buffer doesn't match expected:", key, next)
+		}
+
+		_, _, ok, _ = buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true, expecting false")
+		}
+	})
+
+	t.Run("KeyPendingAgainAfterRead", func(t *testing.T) {
+		buffer := NewConflating[int, int]()
+
+		buffer.Push(1, 1)
+		buffer.Push(2, 2)
+		buffer.TryNext()
+		buffer.Push(1, 3)
+
+		key, next, _, _ := buffer.TryNext()
+		if key != 2 || next != 2 {
+			t.Fatal("value read from buffer doesn't match expected:", key, next)
+		}
+		key, next, _, _ = buffer.TryNext()
+		if key != 1 || next != 3 {
+			t.Fatal("value read from buffer doesn't match expected:", key, next)
+		}
+	})
+
+	t.Run("FirstDirtiedOrder", func(t *testing.T) {
+		buffer := NewConflating[int, int]()
+
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i, i)
+			if i%3 == 0 {
+				key, _, ok, _ := buffer.TryNext()
+				if !ok || key != i/3 {
+					t.Fatal("keys read out of order")
+				}
+			}
+		}
+
+		for i := 334; i < 1000; i++ {
+			key, _, ok, _ := buffer.TryNext()
+			if !ok || key != i {
+				t.Fatal("keys read out of order")
+			}
+		}
+	})
+
+	t.Run("MultipleWriter", func(t *testing.T) {
+		writerCount := 10
+		buffer := NewConflating[string, int]()
+
+		var wg sync.WaitGroup
+		wg.Add(writerCount)
+		for i := 0; i < writerCount; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					buffer.Push(fmt.Sprint(j%10), j)
+				}
+			}()
+		}
+		wg.Wait()
+
+		read := 0
+		totalConflated := 0
+		for {
+			_, next, ok, conflated := buffer.TryNext()
+			if !ok {
+				break
+			}
+			if next < 990 {
+				t.Fatal("value read from buffer isn't the latest:", next)
+			}
+			read++
+			totalConflated += conflated
+		}
+
+		if read != 10 || read+totalConflated != writerCount*1000 {
+			t.Fatal("number of read and conflated values doesn't match expected:", read, totalConflated)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package ringo
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ Buffer[any] = &Deduplicated[any, any]{}
+
+// Deduplicated define a Buffer decorator that suppresses values whose key was
+// pushed less than a time window ago. Memory usage is bounded by the number of
+// distinct keys pushed within two windows.
+type Deduplicated[K comparable, T any] struct {
+	Buffer[T]
+	key    func(T) K
+	window time.Duration
+	now    func() time.Time
+
+	mu sync.Mutex
+	// Time of last pushed value of each key.
+	seen      map[K]time.Time
+	lastSweep time.Time
+
+	suppressed atomic.Uint64
+}
+
+// DeduplicatedOption can be used to setup the Deduplicated buffer.
+type DeduplicatedOption[K comparable, T any] func(*Deduplicated[K, T])
+
+// WithDeduplicatedClock sets clock used to timestamp keys. Default is
+// time.Now.
+func WithDeduplicatedClock[K comparable, T any](now func() time.Time) DeduplicatedOption[K, T] {
+	return func(d *Deduplicated[K, T]) {
+		d.now = now
+	}
+}
+
+// NewDeduplicated returns a new Deduplicated buffer wrapping the given one. A
+// value is suppressed if a value with the same key was pushed less than
+// window ago.
+func NewDeduplicated[K comparable, T any](buffer Buffer[T], key func(T) K, window time.Duration, options ...DeduplicatedOption[K, T]) *Deduplicated[K, T] {
+	if window <= 0 {
+		panic("deduplication window can't be negative or zero")
+	}
+
+	d := &Deduplicated[K, T]{
+		Buffer: buffer,
+		key:    key,
+		window: window,
+		now:    time.Now,
+		seen:   make(map[K]time.Time),
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	d.lastSweep = d.now()
+
+	return d
+}
+
+// Push implements Buffer. Data is suppressed if its key was pushed within
+// window.
+func (d *Deduplicated[K, T]) Push(data T) {
+	if !d.accept(d.key(data)) {
+		d.suppressed.Add(1)
+		return
+	}
+
+	d.Buffer.Push(data)
+}
+
+func (d *Deduplicated[K, T]) accept(key K) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	cutoff := now.Add(-d.window)
+
+	// Forget expired keys once per window.
+	if d.lastSweep.Before(cutoff) {
+		for k, at := range d.seen {
+			if !at.After(cutoff) {
+				delete(d.seen, k)
+			}
+		}
+		d.lastSweep = now
+	}
+
+	if at, ok := d.seen[key]; ok && at.After(cutoff) {
+		return false
+	}
+
+	d.seen[key] = now
+	return true
+}
+
+// Suppressed returns total number of duplicate values suppressed.
+func (d *Deduplicated[K, T]) Suppressed() uint64 {
+	return d.suppressed.Load()
+}
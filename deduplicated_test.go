@@ -0,0 +1,82 @@
+package ringo
+
+import (
+	"testing"
+	"time"
+)
+
+type dedupEvent struct {
+	id   string
+	data int
+}
+
+func TestDeduplicated(t *testing.T) {
+	newDeduplicated := func(size int, window time.Duration) (*Deduplicated[string, dedupEvent], *fakeClock) {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		key := func(ev dedupEvent) string { return ev.id }
+		return NewDeduplicated[string, dedupEvent](NewRing[dedupEvent](size), key, window,
+			WithDeduplicatedClock[string, dedupEvent](clock.Now)), clock
+	}
+
+	t.Run("SuppressDuplicates", func(t *testing.T) {
+		buffer, clock := newDeduplicated(100, time.Minute)
+		buffer.Push(dedupEvent{"a", 1})
+		buffer.Push(dedupEvent{"b", 2})
+		clock.Advance(30 * time.Second)
+		buffer.Push(dedupEvent{"a", 3})
+
+		// Window is relative to last pushed value of the key.
+		clock.Advance(31 * time.Second)
+		buffer.Push(dedupEvent{"a", 4})
+		buffer.Push(dedupEvent{"a", 5})
+
+		for _, expected := range []int{1, 2, 4} {
+			next, ok, _ := buffer.TryNext()
+			if !ok || next.data != expected {
+				t.Fatal("value read from buffer doesn't match expected:", next)
+			}
+		}
+		if _, ok, _ := buffer.TryNext(); ok {
+			t.Fatal("a duplicate value was pushed")
+		}
+		if buffer.Suppressed() != 2 {
+			t.Fatal("Suppressed() returned", buffer.Suppressed())
+		}
+	})
+
+	t.Run("ForgetExpiredKeys", func(t *testing.T) {
+		buffer, clock := newDeduplicated(100, time.Minute)
+		for i := 0; i < 100; i++ {
+			buffer.Push(dedupEvent{id: string(rune('a' + i))})
+		}
+
+		clock.Advance(2 * time.Minute)
+		buffer.Push(dedupEvent{id: "new"})
+
+		if len(buffer.seen) != 1 {
+			t.Fatalf("%v keys remembered, expecting 1", len(buffer.seen))
+		}
+	})
+
+	t.Run("SeparateFromDropped", func(t *testing.T) {
+		buffer, _ := newDeduplicated(10, time.Minute)
+		for i := 0; i < 100; i++ {
+			buffer.Push(dedupEvent{id: string(rune('a' + i%50)), data: i})
+		}
+
+		// 50 distinct keys were pushed to a ring of size 10.
+		_, _, dropped := buffer.TryNext()
+		if dropped != 40 || buffer.Suppressed() != 50 {
+			t.Fatalf("%v values dropped and %v suppressed, expecting 40 and 50", dropped, buffer.Suppressed())
+		}
+	})
+	t.Run("InvalidWindow", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("NewDeduplicated() didn't panic on zero window")
+			}
+		}()
+
+		newDeduplicated(10, 0)
+	})
+}
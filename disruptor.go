@@ -0,0 +1,205 @@
+package ringo
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// sequence is a counter padded to avoid false sharing.
+type sequence struct {
+	_     [7]uint64
+	value atomic.Uint64
+	_     [7]uint64
+}
+
+// Disruptor define a bounded ring buffer of preallocated slots shared by a
+// single producer and multiple dependent consumers, in the spirit of the LMAX
+// Disruptor. Slots are mutated in place, so values are never copied between
+// stages of a pipeline. Unlike other buffers of this package, Disruptor isn't
+// lossy: producer waits for the slowest consumer.
+//
+// Producer claims a slot using Claim, fills it and publishes it using Publish.
+// Consumers read published slots in order and never overtake the consumers
+// they depend on (see SequenceBarrier).
+type Disruptor[T any] struct {
+	slots []T
+	// Number of published slots.
+	cursor sequence
+	// Number of claimed slots, only used by producer.
+	next uint64
+
+	mu sync.Mutex
+	// Sequences of consumers, producer never overtakes them.
+	gating atomic.Pointer[[]*sequence]
+}
+
+// NewDisruptor returns a new Disruptor with the given number of slots.
+func NewDisruptor[T any](size int) *Disruptor[T] {
+	if size <= 0 {
+		panic("ring buffer size can't be negative or zero")
+	}
+
+	d := &Disruptor[T]{
+		slots: make([]T, size),
+	}
+	d.gating.Store(&[]*sequence{})
+
+	return d
+}
+
+// Size returns number of slots.
+func (d *Disruptor[T]) Size() int {
+	return len(d.slots)
+}
+
+// Claim waits until next slot is processed by all consumers and returns it
+// with its sequence number. Slot must be published using Publish once filled.
+// Claim must be called by a single producer go-routine.
+func (d *Disruptor[T]) Claim(ctx context.Context) (seq uint64, slot *T, err error) {
+	seq = d.next
+	size := uint64(d.Size())
+
+	for spin := 0; seq >= d.minGating()+size; spin++ {
+		err = waitSpin(ctx, spin)
+		if err != nil {
+			return
+		}
+	}
+
+	d.next++
+
+	return seq, &d.slots[seq%size], nil
+}
+
+// Publish makes slot with the given sequence number available to consumers.
+// Slots must be published in order.
+func (d *Disruptor[T]) Publish(seq uint64) {
+	d.cursor.value.Store(seq + 1)
+}
+
+func (d *Disruptor[T]) minGating() uint64 {
+	lowest := d.cursor.value.Load()
+	for _, seq := range *d.gating.Load() {
+		if v := seq.value.Load(); v < lowest {
+			lowest = v
+		}
+	}
+
+	return lowest
+}
+
+// Barrier returns a SequenceBarrier that tracks published slots processed by
+// all the given consumers. If no consumer is given, barrier tracks published
+// slots.
+func (d *Disruptor[T]) Barrier(deps ...*DisruptorConsumer[T]) *SequenceBarrier {
+	barrier := &SequenceBarrier{
+		cursor: &d.cursor,
+		deps:   make([]*sequence, len(deps)),
+	}
+	for i, dep := range deps {
+		barrier.deps[i] = &dep.sequence
+	}
+
+	return barrier
+}
+
+// NewConsumer returns a new consumer that reads slots available behind the
+// given barrier. Consumer starts at the next slot to be published.
+func (d *Disruptor[T]) NewConsumer(barrier *SequenceBarrier) *DisruptorConsumer[T] {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c := &DisruptorConsumer[T]{
+		disruptor: d,
+		barrier:   barrier,
+	}
+	c.sequence.value.Store(d.cursor.value.Load())
+
+	gating := append([]*sequence{}, *d.gating.Load()...)
+	gating = append(gating, &c.sequence)
+	d.gating.Store(&gating)
+
+	return c
+}
+
+// SequenceBarrier coordinates consumers of a Disruptor so a consumer never
+// overtakes producer or the consumers it depends on.
+type SequenceBarrier struct {
+	cursor *sequence
+	deps   []*sequence
+}
+
+// WaitFor waits until slot with the given sequence number is available behind
+// the barrier and returns the number of available slots, that is, the
+// sequence number of the first unavailable slot.
+func (sb *SequenceBarrier) WaitFor(ctx context.Context, seq uint64) (uint64, error) {
+	for spin := 0; ; spin++ {
+		available := sb.available()
+		if available > seq {
+			return available, nil
+		}
+
+		err := waitSpin(ctx, spin)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (sb *SequenceBarrier) available() uint64 {
+	lowest := sb.cursor.value.Load()
+	for _, seq := range sb.deps {
+		if v := seq.value.Load(); v < lowest {
+			lowest = v
+		}
+	}
+
+	return lowest
+}
+
+// waitSpin yields processor and checks context every few spins.
+func waitSpin(ctx context.Context, spin int) error {
+	if spin%64 == 63 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	runtime.Gosched()
+	return nil
+}
+
+// DisruptorConsumer define a stage of a Disruptor pipeline. It must be used by
+// a single go-routine.
+type DisruptorConsumer[T any] struct {
+	disruptor *Disruptor[T]
+	barrier   *SequenceBarrier
+	// Number of processed slots.
+	sequence sequence
+}
+
+// Process waits until at least one slot is available and calls fn on every
+// available slot in order. Slots are released to dependent consumers and
+// producer once all of them are processed.
+func (c *DisruptorConsumer[T]) Process(ctx context.Context, fn func(seq uint64, slot *T)) error {
+	next := c.sequence.value.Load()
+	available, err := c.barrier.WaitFor(ctx, next)
+	if err != nil {
+		return err
+	}
+
+	size := uint64(c.disruptor.Size())
+	for seq := next; seq < available; seq++ {
+		fn(seq, &c.disruptor.slots[seq%size])
+	}
+	c.sequence.value.Store(available)
+
+	return nil
+}
+
+// Sequence returns number of slots processed by consumer.
+func (c *DisruptorConsumer[T]) Sequence() uint64 {
+	return c.sequence.value.Load()
+}
@@ -0,0 +1,139 @@
+package ringo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type disruptorTestEvent struct {
+	value    int
+	decoded  bool
+	enriched bool
+}
+
+func TestDisruptor(t *testing.T) {
+	t.Run("Pipeline", func(t *testing.T) {
+		count := 10000
+		d := NewDisruptor[disruptorTestEvent](16)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		decode := d.NewConsumer(d.Barrier())
+		enrich := d.NewConsumer(d.Barrier(decode))
+		persist := d.NewConsumer(d.Barrier(enrich))
+
+		errs := make(chan error, 3)
+		runStage := func(c *DisruptorConsumer[disruptorTestEvent], fn func(seq uint64, ev *disruptorTestEvent) error) {
+			var stageErr error
+			for c.Sequence() < uint64(count) && stageErr == nil {
+				err := c.Process(ctx, func(seq uint64, ev *disruptorTestEvent) {
+					if stageErr == nil {
+						stageErr = fn(seq, ev)
+					}
+				})
+				if err != nil {
+					stageErr = err
+				}
+			}
+			errs <- stageErr
+		}
+
+		go runStage(decode, func(seq uint64, ev *disruptorTestEvent) error {
+			if ev.value != int(seq) {
+				return errors.New("decode stage read an unpublished slot")
+			}
+			ev.decoded = true
+			return nil
+		})
+		go runStage(enrich, func(seq uint64, ev *disruptorTestEvent) error {
+			if !ev.decoded {
+				return errors.New("enrich stage overtook decode stage")
+			}
+			ev.enriched = true
+			return nil
+		})
+
+		persisted := 0
+		go runStage(persist, func(seq uint64, ev *disruptorTestEvent) error {
+			if !ev.enriched {
+				return errors.New("persist stage overtook enrich stage")
+			}
+			if ev.value != persisted {
+				return errors.New("persist stage read slots out of order")
+			}
+			persisted++
+			return nil
+		})
+
+		for i := 0; i < count; i++ {
+			seq, ev, err := d.Claim(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*ev = disruptorTestEvent{value: i}
+			d.Publish(seq)
+		}
+
+		for i := 0; i < 3; i++ {
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+		}
+		if persisted != count {
+			t.Fatalf("number of persisted events doesn't match expected, expected %v got %v", count, persisted)
+		}
+	})
+
+	t.Run("ProducerWaitsForConsumers", func(t *testing.T) {
+		d := NewDisruptor[int](4)
+		c := d.NewConsumer(d.Barrier())
+
+		for i := 0; i < 4; i++ {
+			seq, slot, err := d.Claim(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			*slot = i
+			d.Publish(seq)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, _, err := d.Claim(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("Claim() didn't wait for consumer:", err)
+		}
+
+		read := []int{}
+		err = c.Process(context.Background(), func(_ uint64, slot *int) {
+			read = append(read, *slot)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(read) != 4 || read[0] != 0 || read[3] != 3 {
+			t.Fatal("values read from disruptor doesn't match expected:", read)
+		}
+
+		_, _, err = d.Claim(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("ConsumerWaitsForProducer", func(t *testing.T) {
+		d := NewDisruptor[int](4)
+		c := d.NewConsumer(d.Barrier())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := c.Process(ctx, func(_ uint64, _ *int) {
+			t.Fatal("consumer read an unpublished slot")
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatal("Process() didn't wait for producer:", err)
+		}
+	})
+}
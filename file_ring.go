@@ -0,0 +1,400 @@
+package ringo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// File layout:
+//
+//	header | cursor record 0 | cursor record 1 | padding | slot 0 | slot 1 | ...
+//
+// A slot contains a sequence number, the length of the encoded value and a
+// checksum followed by the encoded value. Read cursor is persisted
+// alternatively in one of the two cursor records so a torn write never
+// corrupts both.
+const (
+	fileRingMagic          = "RINGOFR1"
+	fileRingVersion        = 1
+	fileRingHeaderSize     = 64
+	fileRingCursorSize     = 16
+	fileRingSlotsOffset    = 128
+	fileRingSlotHeaderSize = 16
+)
+
+var (
+	// ErrInvalidFileRing is returned when opening a file that isn't a valid
+	// FileRing or whose layout doesn't match requested one.
+	ErrInvalidFileRing = errors.New("ringo: invalid file ring")
+	// ErrValueTooLarge is reported when an encoded value doesn't fit in a
+	// slot.
+	ErrValueTooLarge = errors.New("ringo: encoded value larger than slot size")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+var _ Buffer[any] = &FileRing[any]{}
+
+// FileRing define a ring buffer persisted in a fixed-size file. Values are
+// serialized using a Codec and stored in slots of fixed size. Slots and read
+// cursor survive restarts: reopening a FileRing resumes from the last
+// persisted read cursor. Writes that were interrupted by a crash are detected
+// using checksums, skipped and reported as dropped.
+//
+// As Push and TryNext can't return errors, I/O and codec errors are recorded
+// and returned by Err. A value that can't be written is lost.
+//
+// FileRing is safe for concurrent use.
+type FileRing[T any] struct {
+	mu       sync.Mutex
+	storage  fileRingStorage
+	codec    Codec[T]
+	mmap     bool
+	slotSize uint64
+	capacity uint64
+
+	writeIndex uint64
+	readIndex  uint64
+	// Next cursor record to write.
+	cursorRecord int
+	// Values dropped since last read.
+	dropped int
+
+	slot []byte
+	err  error
+}
+
+type fileRingStorage interface {
+	io.ReaderAt
+	io.WriterAt
+	Sync() error
+	Close() error
+}
+
+// FileRingOption can be used to setup the FileRing.
+type FileRingOption[T any] func(*FileRing[T])
+
+// WithFileRingMmap makes FileRing access its file through a shared memory
+// mapping instead of read and write system calls. It is only supported on
+// Linux, OpenFileRing returns an error on other platforms.
+func WithFileRingMmap[T any]() FileRingOption[T] {
+	return func(fr *FileRing[T]) {
+		fr.mmap = true
+	}
+}
+
+// OpenFileRing opens or creates a FileRing stored at the given path with the
+// given number of slots of slotSize bytes. If file already exists, its layout
+// must match size and slotSize.
+func OpenFileRing[T any](path string, size, slotSize int, codec Codec[T], options ...FileRingOption[T]) (*FileRing[T], error) {
+	if size <= 0 {
+		panic("ring buffer size can't be negative or zero")
+	}
+	if slotSize <= 0 {
+		panic("ring buffer slot size can't be negative or zero")
+	}
+
+	fr := &FileRing[T]{
+		codec:    codec,
+		slotSize: uint64(slotSize),
+		capacity: uint64(size),
+		slot:     make([]byte, fileRingSlotHeaderSize+slotSize),
+	}
+
+	for _, opt := range options {
+		opt(fr)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fr.init(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if fr.mmap {
+		fr.storage, err = mmapFileRingStorage(f, fr.fileSize())
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	} else {
+		fr.storage = f
+	}
+
+	err = fr.recover()
+	if err != nil {
+		_ = fr.storage.Close()
+		return nil, err
+	}
+
+	return fr, nil
+}
+
+func (fr *FileRing[T]) fileSize() int64 {
+	return int64(fileRingSlotsOffset + fr.capacity*(fileRingSlotHeaderSize+fr.slotSize))
+}
+
+// init writes header of new file or validates header of existing one.
+func (fr *FileRing[T]) init(f *os.File) error {
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	var header [fileRingHeaderSize]byte
+
+	if stat.Size() == 0 {
+		copy(header[:], fileRingMagic)
+		binary.LittleEndian.PutUint32(header[8:], fileRingVersion)
+		binary.LittleEndian.PutUint32(header[12:], uint32(fr.slotSize))
+		binary.LittleEndian.PutUint64(header[16:], fr.capacity)
+		binary.LittleEndian.PutUint32(header[24:], crc32.Checksum(header[:24], crcTable))
+
+		err = f.Truncate(fr.fileSize())
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteAt(header[:], 0)
+		if err != nil {
+			return err
+		}
+		return f.Sync()
+	}
+
+	if stat.Size() != fr.fileSize() {
+		return fmt.Errorf("%w: file size doesn't match size and slot size", ErrInvalidFileRing)
+	}
+
+	_, err = f.ReadAt(header[:], 0)
+	if err != nil {
+		return err
+	}
+
+	if string(header[:8]) != fileRingMagic ||
+		binary.LittleEndian.Uint32(header[24:]) != crc32.Checksum(header[:24], crcTable) {
+		return fmt.Errorf("%w: corrupted header", ErrInvalidFileRing)
+	}
+	if binary.LittleEndian.Uint32(header[8:]) != fileRingVersion {
+		return fmt.Errorf("%w: unsupported version", ErrInvalidFileRing)
+	}
+	if uint64(binary.LittleEndian.Uint32(header[12:])) != fr.slotSize ||
+		binary.LittleEndian.Uint64(header[16:]) != fr.capacity {
+		return fmt.Errorf("%w: size and slot size doesn't match", ErrInvalidFileRing)
+	}
+
+	return nil
+}
+
+// recover restores write index from slots and read index from cursor records.
+func (fr *FileRing[T]) recover() error {
+	// Sequences starts at 1, 0 means empty slot.
+	fr.writeIndex = 0
+	fr.readIndex = 1
+
+	for i := uint64(0); i < fr.capacity; i++ {
+		seq, _, valid, err := fr.readSlot(i)
+		if err != nil {
+			return err
+		}
+		if valid && seq > fr.writeIndex {
+			fr.writeIndex = seq
+		}
+	}
+
+	var record [fileRingCursorSize]byte
+	for i := 0; i < 2; i++ {
+		_, err := fr.storage.ReadAt(record[:], int64(fileRingHeaderSize+i*fileRingCursorSize))
+		if err != nil {
+			return err
+		}
+		if binary.LittleEndian.Uint32(record[8:]) != crc32.Checksum(record[:8], crcTable) {
+			continue
+		}
+
+		readIndex := binary.LittleEndian.Uint64(record[:8])
+		if readIndex > fr.readIndex {
+			fr.readIndex = readIndex
+			// Overwrite other record first.
+			fr.cursorRecord = 1 - i
+		}
+	}
+
+	// Last writes may have been lost.
+	if fr.readIndex > fr.writeIndex+1 {
+		fr.readIndex = fr.writeIndex + 1
+	}
+
+	return nil
+}
+
+// readSlot reads slot at the given index into fr.slot and returns its
+// sequence number and encoded value. valid is false if slot is empty or
+// corrupted.
+func (fr *FileRing[T]) readSlot(index uint64) (seq uint64, data []byte, valid bool, err error) {
+	offset := int64(fileRingSlotsOffset + index*uint64(len(fr.slot)))
+
+	header := fr.slot[:fileRingSlotHeaderSize]
+	_, err = fr.storage.ReadAt(header, offset)
+	if err != nil {
+		return
+	}
+
+	seq = binary.LittleEndian.Uint64(header)
+	length := uint64(binary.LittleEndian.Uint32(header[8:]))
+	if seq == 0 || length > fr.slotSize {
+		return
+	}
+
+	data = fr.slot[fileRingSlotHeaderSize : fileRingSlotHeaderSize+length]
+	_, err = fr.storage.ReadAt(data, offset+fileRingSlotHeaderSize)
+	if err != nil {
+		return
+	}
+
+	crc := crc32.Update(crc32.Checksum(header[:12], crcTable), crcTable, data)
+	valid = crc == binary.LittleEndian.Uint32(header[12:])
+
+	return
+}
+
+func (fr *FileRing[T]) writeCursor() error {
+	var record [fileRingCursorSize]byte
+	binary.LittleEndian.PutUint64(record[:], fr.readIndex)
+	binary.LittleEndian.PutUint32(record[8:], crc32.Checksum(record[:8], crcTable))
+
+	_, err := fr.storage.WriteAt(record[:], int64(fileRingHeaderSize+fr.cursorRecord*fileRingCursorSize))
+	fr.cursorRecord = 1 - fr.cursorRecord
+
+	return err
+}
+
+func (fr *FileRing[T]) setErr(err error) {
+	if fr.err == nil {
+		fr.err = err
+	}
+}
+
+// Size implements Buffer.
+func (fr *FileRing[T]) Size() int {
+	return int(fr.capacity)
+}
+
+// Push implements Buffer.
+func (fr *FileRing[T]) Push(data T) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	slot, err := fr.codec.Encode(fr.slot[:fileRingSlotHeaderSize], data)
+	if err != nil {
+		fr.setErr(err)
+		return
+	}
+	if uint64(len(slot)) > uint64(len(fr.slot)) {
+		fr.setErr(ErrValueTooLarge)
+		return
+	}
+
+	writeIndex := fr.writeIndex + 1
+	binary.LittleEndian.PutUint64(slot, writeIndex)
+	binary.LittleEndian.PutUint32(slot[8:], uint32(len(slot)-fileRingSlotHeaderSize))
+	crc := crc32.Update(crc32.Checksum(slot[:12], crcTable), crcTable, slot[fileRingSlotHeaderSize:])
+	binary.LittleEndian.PutUint32(slot[12:], crc)
+
+	index := writeIndex % fr.capacity
+	_, err = fr.storage.WriteAt(slot, int64(fileRingSlotsOffset+index*uint64(len(fr.slot))))
+	if err != nil {
+		fr.setErr(err)
+		return
+	}
+
+	fr.writeIndex = writeIndex
+}
+
+// TryNext implements Buffer.
+func (fr *FileRing[T]) TryNext() (result T, ok bool, dropped int) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	for {
+		// read index is ahead of write index.
+		if fr.readIndex > fr.writeIndex {
+			return
+		}
+
+		seq, data, valid, err := fr.readSlot(fr.readIndex % fr.capacity)
+		if err != nil {
+			fr.setErr(err)
+			return
+		}
+		if !valid || seq < fr.readIndex {
+			// Slot was torn or never reached the disk before a crash, skip it.
+			fr.dropped++
+			fr.readIndex++
+			continue
+		}
+
+		// writer is faster that reader and have overwritten data.
+		if seq > fr.readIndex {
+			fr.dropped += int(seq - fr.readIndex)
+			fr.readIndex = seq
+		}
+
+		fr.readIndex++
+		err = fr.writeCursor()
+		if err != nil {
+			fr.setErr(err)
+		}
+
+		value, err := fr.codec.Decode(data)
+		if err != nil {
+			// Skip value like a torn slot, newer ones may be readable.
+			fr.setErr(err)
+			fr.dropped++
+			continue
+		}
+
+		dropped = fr.dropped
+		fr.dropped = 0
+
+		return value, true, dropped
+	}
+}
+
+// Err returns the first error encountered by Push or TryNext.
+func (fr *FileRing[T]) Err() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	return fr.err
+}
+
+// Sync commits content of the ring buffer to stable storage.
+func (fr *FileRing[T]) Sync() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	return fr.storage.Sync()
+}
+
+// Close syncs and closes underlying file.
+func (fr *FileRing[T]) Close() error {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	err := fr.storage.Sync()
+	if cerr := fr.storage.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package ringo
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// mmapStorage is a fileRingStorage backed by a shared memory mapping of a
+// file.
+type mmapStorage struct {
+	f    *os.File
+	data []byte
+}
+
+func mmapFileRingStorage(f *os.File, size int64) (fileRingStorage, error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mmapStorage{f: f, data: data}, nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (ms *mmapStorage) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(ms.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, ms.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// WriteAt implements io.WriterAt.
+func (ms *mmapStorage) WriteAt(p []byte, off int64) (int, error) {
+	if off+int64(len(p)) > int64(len(ms.data)) {
+		return 0, io.ErrShortWrite
+	}
+
+	return copy(ms.data[off:], p), nil
+}
+
+// Sync flushes memory mapping to disk. On Linux, fsync writes back dirty pages
+// of shared mappings, other platforms require msync.
+func (ms *mmapStorage) Sync() error {
+	return ms.f.Sync()
+}
+
+// Close unmaps memory and closes file.
+func (ms *mmapStorage) Close() error {
+	err := syscall.Munmap(ms.data)
+	if cerr := ms.f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
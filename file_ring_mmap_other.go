@@ -0,0 +1,12 @@
+//go:build !linux
+
+package ringo
+
+import (
+	"errors"
+	"os"
+)
+
+func mmapFileRingStorage(_ *os.File, _ int64) (fileRingStorage, error) {
+	return nil, errors.New("ringo: memory mapped file ring not supported on this platform")
+}
@@ -0,0 +1,274 @@
+package ringo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func openTestFileRing(t *testing.T, path string, size int, options ...FileRingOption[int64]) *FileRing[int64] {
+	t.Helper()
+
+	fr, err := OpenFileRing[int64](path, size, 8, BinaryCodec[int64]{}, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fr
+}
+
+func expectNext(t *testing.T, buffer Buffer[int64], expected int64, expectedDropped int) {
+	t.Helper()
+
+	next, ok, dropped := buffer.TryNext()
+	if !ok {
+		t.Fatal("TryNext() returned false, expecting true")
+	}
+	if dropped != expectedDropped {
+		t.Fatalf("buffer reported wrong number of dropped value, expected %v got %v", expectedDropped, dropped)
+	}
+	if next != expected {
+		t.Fatalf("value read from buffer doesn't match expected, expected %v got %v", expected, next)
+	}
+}
+
+func expectEmpty(t *testing.T, buffer Buffer[int64]) {
+	t.Helper()
+
+	_, ok, _ := buffer.TryNext()
+	if ok {
+		t.Fatal("TryNext() returned true, expecting false")
+	}
+}
+
+func TestFileRing(t *testing.T) {
+	for _, mmap := range []bool{false, true} {
+		var options []FileRingOption[int64]
+		name := "ReadWrite"
+		if mmap {
+			options = append(options, WithFileRingMmap[int64]())
+			name = "Mmap"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			if mmap && runtime.GOOS != "linux" {
+				t.Skip("memory mapped file ring is only supported on linux")
+			}
+
+			t.Run("SequentialReadWrite", func(t *testing.T) {
+				fr := openTestFileRing(t, filepath.Join(t.TempDir(), "ring"), 100, options...)
+				defer fr.Close()
+
+				for i := int64(0); i < 1000; i++ {
+					fr.Push(i)
+					expectNext(t, fr, i, 0)
+				}
+				expectEmpty(t, fr)
+
+				if fr.Err() != nil {
+					t.Fatal(fr.Err())
+				}
+			})
+
+			t.Run("ResumeAfterReopen", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "ring")
+				fr := openTestFileRing(t, path, 100, options...)
+				for i := int64(0); i < 10; i++ {
+					fr.Push(i)
+				}
+				for i := int64(0); i < 3; i++ {
+					expectNext(t, fr, i, 0)
+				}
+				if err := fr.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				fr = openTestFileRing(t, path, 100, options...)
+				defer fr.Close()
+
+				for i := int64(3); i < 10; i++ {
+					expectNext(t, fr, i, 0)
+				}
+				expectEmpty(t, fr)
+
+				fr.Push(10)
+				expectNext(t, fr, 10, 0)
+			})
+
+			t.Run("DroppedDataAfterReopen", func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "ring")
+				fr := openTestFileRing(t, path, 100, options...)
+				for i := int64(0); i < 1000; i++ {
+					fr.Push(i)
+				}
+				if err := fr.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				fr = openTestFileRing(t, path, 100, options...)
+				defer fr.Close()
+
+				expectNext(t, fr, 900, 900)
+			})
+		})
+	}
+
+	t.Run("TornSlotWrite", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		fr := openTestFileRing(t, path, 100)
+		for i := int64(0); i < 5; i++ {
+			fr.Push(i)
+		}
+		if err := fr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate a crash in the middle of the write of the 5th value.
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := int64(fileRingSlotsOffset + 5*(fileRingSlotHeaderSize+8) + fileRingSlotHeaderSize + 4)
+		_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+
+		fr = openTestFileRing(t, path, 100)
+		defer fr.Close()
+
+		for i := int64(0); i < 4; i++ {
+			expectNext(t, fr, i, 0)
+		}
+		expectEmpty(t, fr)
+
+		// Torn slot is reused.
+		fr.Push(42)
+		expectNext(t, fr, 42, 0)
+	})
+
+	t.Run("TornMiddleSlot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		fr := openTestFileRing(t, path, 100)
+		for i := int64(0); i < 5; i++ {
+			fr.Push(i)
+		}
+		if err := fr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Writeback order isn't guaranteed, any slot may be torn after a
+		// crash. Corrupt the 3rd value.
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset := int64(fileRingSlotsOffset + 3*(fileRingSlotHeaderSize+8) + fileRingSlotHeaderSize + 4)
+		_, err = f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+
+		fr = openTestFileRing(t, path, 100)
+		defer fr.Close()
+
+		expectNext(t, fr, 0, 0)
+		expectNext(t, fr, 1, 0)
+		expectNext(t, fr, 3, 1)
+		expectNext(t, fr, 4, 0)
+		expectEmpty(t, fr)
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		writer, err := OpenFileRing[string](path, 100, 8, StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Push("x")
+		writer.Push("3")
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		reader, err := OpenFileRing[int64](path, 100, 8, JSONCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		// Value that couldn't be decoded is skipped and reported.
+		expectNext(t, reader, 3, 1)
+		if reader.Err() == nil {
+			t.Fatal("decode error wasn't reported")
+		}
+		expectEmpty(t, reader)
+	})
+
+	t.Run("TornCursorWrite", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		fr := openTestFileRing(t, path, 100)
+		for i := int64(0); i < 5; i++ {
+			fr.Push(i)
+		}
+		for i := int64(0); i < 3; i++ {
+			expectNext(t, fr, i, 0)
+		}
+		// Last read was persisted in this record.
+		lastRecord := 1 - fr.cursorRecord
+		if err := fr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate a crash in the middle of the last cursor write.
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f.WriteAt([]byte{0xff}, int64(fileRingHeaderSize+lastRecord*fileRingCursorSize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+
+		fr = openTestFileRing(t, path, 100)
+		defer fr.Close()
+
+		// Last read value is read again.
+		for i := int64(2); i < 5; i++ {
+			expectNext(t, fr, i, 0)
+		}
+		expectEmpty(t, fr)
+	})
+
+	t.Run("LayoutMismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		fr := openTestFileRing(t, path, 100)
+		if err := fr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := OpenFileRing[int64](path, 50, 8, BinaryCodec[int64]{})
+		if !errors.Is(err, ErrInvalidFileRing) {
+			t.Fatal("OpenFileRing() didn't return ErrInvalidFileRing:", err)
+		}
+	})
+
+	t.Run("ValueTooLarge", func(t *testing.T) {
+		fr, err := OpenFileRing[int64](filepath.Join(t.TempDir(), "ring"), 100, 4, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fr.Close()
+
+		fr.Push(1)
+		if !errors.Is(fr.Err(), ErrValueTooLarge) {
+			t.Fatal("Err() didn't return ErrValueTooLarge:", fr.Err())
+		}
+		expectEmpty(t, fr)
+	})
+}
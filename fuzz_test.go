@@ -0,0 +1,186 @@
+package ringo
+
+import (
+	"math"
+	"testing"
+)
+
+// lossyModel is a reference model of lossy ring buffers: it holds the last
+// size written values and a reader lapped by writers jumps to the held value
+// stored in the same slot as the one it expected, reporting skipped values as
+// dropped.
+type lossyModel struct {
+	size uint64
+	// Sequence number of first write.
+	base   uint64
+	values []int
+	// Next sequence number to read.
+	read uint64
+}
+
+func (m *lossyModel) push(value int) {
+	m.values = append(m.values, value)
+}
+
+// next returns value that should be read next without consuming it.
+func (m *lossyModel) next() (value int, seq uint64, ok bool, dropped int) {
+	if len(m.values) == 0 {
+		return
+	}
+	// Sequence numbers may wrap, compare distances from base.
+	last := m.base + uint64(len(m.values)) - 1
+	if m.read-m.base > last-m.base {
+		return
+	}
+
+	// Latest written sequence stored in the same slot.
+	seq = last - (last-m.read)%m.size
+	return m.values[seq-m.base], seq, true, int(seq - m.read)
+}
+
+// fuzzBuffer is implemented by buffers supporting sequence numbers.
+type fuzzBuffer interface {
+	Buffer[int]
+	TryNextSeq() (int, uint64, bool, int)
+	Seek(uint64) error
+}
+
+const (
+	fuzzOpPush = iota
+	fuzzOpTryNext
+	fuzzOpPeek
+	fuzzOpBatch
+	fuzzOpCount
+)
+
+// fuzzSize maps fuzz input to a buffer size in [1, 1<<20].
+func fuzzSize(size uint32) int {
+	return int(size%(1<<20)) + 1
+}
+
+// fuzzStart returns initial write counter, near math.MaxUint64 if requested.
+// Counters of power of two sizes wrap while running ops, others must not
+// overflow (see "Known issues" in README).
+func fuzzStart(nearMax bool, size int, ops []byte) uint64 {
+	if !nearMax {
+		return 0
+	}
+
+	if size&(size-1) == 0 {
+		return math.MaxUint64 - uint64(len(ops))
+	}
+
+	// Batch op pushes at most 32 values.
+	return math.MaxUint64 - 32*uint64(len(ops)) - 2
+}
+
+// runFuzzOps interprets ops as a sequence of operations and runs them against
+// buffer and model.
+func runFuzzOps(t *testing.T, buffer fuzzBuffer, model *lossyModel, replay bool, ops []byte) {
+	if buffer.Size() != int(model.size) {
+		t.Fatalf("Size() returned %v, expecting %v", buffer.Size(), model.size)
+	}
+
+	for i := 0; i < len(ops); i++ {
+		op := ops[i] % fuzzOpCount
+		arg := int(ops[i] / fuzzOpCount)
+
+		switch op {
+		case fuzzOpPush:
+			buffer.Push(arg)
+			model.push(arg)
+
+		case fuzzOpBatch:
+			for j := 0; j < arg%32+1; j++ {
+				buffer.Push(arg + j)
+				model.push(arg + j)
+			}
+
+		case fuzzOpTryNext, fuzzOpPeek:
+			expected, expectedSeq, expectedOk, expectedDropped := model.next()
+			next, seq, ok, dropped := buffer.TryNextSeq()
+			if ok != expectedOk || dropped != expectedDropped {
+				t.Fatalf("op %v: TryNextSeq() returned ok=%v dropped=%v, expecting ok=%v dropped=%v", i, ok, dropped, expectedOk, expectedDropped)
+			}
+			if !ok {
+				continue
+			}
+			if next != expected || seq != expectedSeq {
+				t.Fatalf("op %v: TryNextSeq() returned %v (seq %v), expecting %v (seq %v)", i, next, seq, expected, expectedSeq)
+			}
+			model.read = seq + 1
+
+			if op == fuzzOpTryNext {
+				continue
+			}
+
+			// Peek: go back to read value.
+			err := buffer.Seek(seq)
+			if replay {
+				if err != nil {
+					t.Fatalf("op %v: Seek(%v) failed: %v", i, seq, err)
+				}
+				model.read = seq
+			} else if err != ErrSequenceUnavailable {
+				t.Fatalf("op %v: Seek(%v) to read value didn't fail", i, seq)
+			}
+		}
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	ops := []byte{}
+	for i := 0; i < 64; i++ {
+		ops = append(ops, byte(i*7))
+	}
+
+	for _, size := range []uint32{1, 2, 3, 7, 10, 64, 100, 1000, 1 << 20} {
+		f.Add(size, false, true, ops)
+		f.Add(size, true, false, ops)
+	}
+	f.Add(uint32(math.MaxUint32), true, true, []byte{fuzzOpBatch + 4*31, fuzzOpPeek, fuzzOpTryNext, fuzzOpTryNext})
+}
+
+func FuzzRing(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, size uint32, nearMax bool, _ bool, ops []byte) {
+		ring := NewRing[int](fuzzSize(size))
+		start := fuzzStart(nearMax, ring.Size(), ops)
+		ring.writeIndex = start
+		ring.readIndex = start + 1
+		if nearMax {
+			// Slots hold values read before start.
+			for i := 0; i < ring.Size(); i++ {
+				seq := start - uint64(i)
+				ring.buffer[seq%uint64(ring.Size())].index = seq
+			}
+		}
+
+		model := &lossyModel{size: uint64(ring.Size()), base: start + 1, read: start + 1}
+		// Ring holds read values until they're overwritten.
+		runFuzzOps(t, ring, model, true, ops)
+	})
+}
+
+func FuzzManyToOne(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, size uint32, nearMax bool, replay bool, ops []byte) {
+		options := []ManyToOneOption[int]{}
+		if replay {
+			options = append(options, WithManyToOneReplay[int]())
+		}
+
+		mto := NewManyToOne[int](fuzzSize(size), options...)
+		start := fuzzStart(nearMax, mto.Size(), ops)
+		if nearMax {
+			mto.writeIndex.Store(start)
+			mto.readIndex.Store(start + 1)
+		}
+		base := mto.writeIndex.Load() + 1
+
+		model := &lossyModel{size: uint64(mto.Size()), base: base, read: base}
+		runFuzzOps(t, mto, model, replay, ops)
+	})
+}
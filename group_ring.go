@@ -0,0 +1,203 @@
+package ringo
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// GroupRing define a ring buffer safe for use by concurrent writers and
+// multiple named consumer groups. Each group has its own read cursor and drop
+// accounting, so a slow group never affects others. Values are distributed
+// across members (readers) of a group: each value is read once per group.
+type GroupRing[T any] struct {
+	buffer           []atomic.Pointer[box[T]]
+	writeIndex       atomic.Uint64
+	collisionHandler CollisionHandler
+
+	mu     sync.Mutex
+	groups map[string]*ConsumerGroup[T]
+	// Copy of groups signaled by Push.
+	signaled atomic.Pointer[[]*ConsumerGroup[T]]
+}
+
+// GroupRingOption can be used to setup the GroupRing.
+type GroupRingOption[T any] func(*GroupRing[T])
+
+// WithGroupRingCollisionHandler sets GroupRing ring buffer collision handler.
+// If this option is not provided ring buffer defaults to global handler.
+func WithGroupRingCollisionHandler[T any](ch CollisionHandler) GroupRingOption[T] {
+	return func(gr *GroupRing[T]) {
+		gr.collisionHandler = ch
+	}
+}
+
+// NewGroupRing returns a new GroupRing ring buffer with the given size.
+func NewGroupRing[T any](size int, options ...GroupRingOption[T]) *GroupRing[T] {
+	if size <= 0 {
+		panic("ring buffer size can't be negative or zero")
+	}
+
+	gr := &GroupRing[T]{
+		buffer:           make([]atomic.Pointer[box[T]], size),
+		collisionHandler: *globalCollisionHandler.Load(),
+		groups:           make(map[string]*ConsumerGroup[T]),
+	}
+	gr.signaled.Store(&[]*ConsumerGroup[T]{})
+
+	// First increment will overflow to 0.
+	gr.writeIndex.Store(math.MaxUint64)
+
+	for _, opt := range options {
+		opt(gr)
+	}
+
+	return gr
+}
+
+// Size returns size of internal buffer.
+func (gr *GroupRing[T]) Size() int {
+	return len(gr.buffer)
+}
+
+// Push data to buffer and wakes up a Waiter of each group.
+func (gr *GroupRing[T]) Push(data T) {
+	pushBox(gr.buffer, &gr.writeIndex, data, func() {
+		gr.collisionHandler.OnCollision(gr)
+	})
+
+	for _, group := range *gr.signaled.Load() {
+		select {
+		case group.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Group returns consumer group with the given name. Group is created if it
+// doesn't exist, its read cursor starts at the next value to be written.
+func (gr *GroupRing[T]) Group(name string) *ConsumerGroup[T] {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	group, ok := gr.groups[name]
+	if !ok {
+		group = &ConsumerGroup[T]{ring: gr, name: name, c: make(chan struct{}, 1)}
+		group.readIndex.Store(gr.writeIndex.Load() + 1)
+		gr.groups[name] = group
+		gr.updateSignaled()
+	}
+
+	return group
+}
+
+// Remove removes consumer group with the given name. Readers holding the group
+// can still use it but Group will return a new group for that name.
+func (gr *GroupRing[T]) Remove(name string) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	delete(gr.groups, name)
+	gr.updateSignaled()
+}
+
+// updateSignaled copies groups signaled by Push. It must be called with mu
+// held.
+func (gr *GroupRing[T]) updateSignaled() {
+	signaled := make([]*ConsumerGroup[T], 0, len(gr.groups))
+	for _, group := range gr.groups {
+		signaled = append(signaled, group)
+	}
+	gr.signaled.Store(&signaled)
+}
+
+var _ Buffer[any] = &ConsumerGroup[any]{}
+
+// ConsumerGroup define a named read cursor of a GroupRing. It is safe for use
+// by concurrent readers (members of the group), each value is read by a single
+// member. Use one Poller or Waiter per member to wait for values, a Waiter is
+// woken up by pushes done through the GroupRing or any of its groups.
+type ConsumerGroup[T any] struct {
+	ring      *GroupRing[T]
+	name      string
+	readIndex atomic.Uint64
+	dropped   atomic.Uint64
+	// Signaled on push, shared by Waiters of the group.
+	c chan struct{}
+}
+
+// Name returns name of the group.
+func (cg *ConsumerGroup[T]) Name() string {
+	return cg.name
+}
+
+// Size implements Buffer.
+func (cg *ConsumerGroup[T]) Size() int {
+	return cg.ring.Size()
+}
+
+// Push implements Buffer. Data is pushed to the underlying GroupRing and
+// is visible to all groups.
+func (cg *ConsumerGroup[T]) Push(data T) {
+	cg.ring.Push(data)
+}
+
+// TryNext implements Buffer.
+func (cg *ConsumerGroup[T]) TryNext() (result T, ok bool, dropped int) {
+	result, _, ok, dropped = cg.TryNextSeq()
+	return
+}
+
+// TryNextSeq is like TryNext but also returns the sequence number of the read
+// value.
+func (cg *ConsumerGroup[T]) TryNextSeq() (result T, seq uint64, ok bool, dropped int) {
+	buffer := cg.ring.buffer
+
+	for {
+		readIndex := cg.readIndex.Load()
+		box := buffer[readIndex%uint64(len(buffer))].Load()
+
+		// already read
+		if box == nil || seqBefore(box.index, readIndex) {
+			return
+		}
+
+		// Another member read this value.
+		if !cg.readIndex.CompareAndSwap(readIndex, box.index+1) {
+			continue
+		}
+
+		// cell have been overwritten
+		if seqBefore(readIndex, box.index) {
+			dropped = int(box.index - readIndex)
+			cg.dropped.Add(uint64(dropped))
+		}
+
+		return box.data, box.index, true, dropped
+	}
+}
+
+// Seek moves read cursor of the group to the given sequence number. Sequence
+// must still be held in the buffer or be the next one to be written, otherwise
+// ErrSequenceUnavailable is returned.
+func (cg *ConsumerGroup[T]) Seek(seq uint64) error {
+	if seq != cg.ring.writeIndex.Load()+1 {
+		box := cg.ring.buffer[seq%uint64(cg.Size())].Load()
+		if box == nil || box.index != seq {
+			return ErrSequenceUnavailable
+		}
+	}
+
+	cg.readIndex.Store(seq)
+	return nil
+}
+
+// signal implements signaler.
+func (cg *ConsumerGroup[T]) signal() chan struct{} {
+	return cg.c
+}
+
+// Dropped returns total number of values dropped by the group.
+func (cg *ConsumerGroup[T]) Dropped() uint64 {
+	return cg.dropped.Load()
+}
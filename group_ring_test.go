@@ -0,0 +1,273 @@
+package ringo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupRing(t *testing.T) {
+	t.Run("IndependentGroups", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		indexer := ring.Group("indexer")
+		metrics := ring.Group("metrics")
+
+		for i := 0; i < 50; i++ {
+			ring.Push(i)
+		}
+
+		for _, group := range []*ConsumerGroup[int]{indexer, metrics} {
+			for i := 0; i < 50; i++ {
+				next, ok, dropped := group.TryNext()
+				if !ok {
+					t.Fatal("TryNext() returned false, expecting true")
+				}
+				if dropped != 0 {
+					t.Fatal("group reported some dropped value:", dropped)
+				}
+				if next != i {
+					t.Fatal("value read from group doesn't match expected")
+				}
+			}
+
+			_, ok, _ := group.TryNext()
+			if ok {
+				t.Fatal("TryNext() returned true, expecting false")
+			}
+		}
+	})
+
+	t.Run("SameGroup", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		if ring.Group("indexer") != ring.Group("indexer") {
+			t.Fatal("Group() returned a different group for the same name")
+		}
+		if ring.Group("indexer").Name() != "indexer" {
+			t.Fatal("group name doesn't match expected")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		group := ring.Group("indexer")
+		ring.Remove("indexer")
+
+		if ring.Group("indexer") == group {
+			t.Fatal("Group() returned a removed group")
+		}
+	})
+
+	t.Run("GroupStartsAtNextValue", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		ring.Push(1)
+
+		group := ring.Group("archiver")
+		_, ok, _ := group.TryNext()
+		if ok {
+			t.Fatal("new group read a value pushed before its creation")
+		}
+
+		ring.Push(2)
+		next, ok, _ := group.TryNext()
+		if !ok || next != 2 {
+			t.Fatal("value read from group doesn't match expected")
+		}
+	})
+
+	t.Run("DroppedPerGroup", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		fast := ring.Group("fast")
+		slow := ring.Group("slow")
+
+		for i := 0; i < 1000; i++ {
+			ring.Push(i)
+			next, _, dropped := fast.TryNext()
+			if next != i || dropped != 0 {
+				t.Fatal("fast group dropped some value")
+			}
+		}
+
+		next, ok, dropped := slow.TryNext()
+		if !ok {
+			t.Fatal("TryNext() returned false, expecting true")
+		}
+		if dropped != 900 || next != 900 {
+			t.Fatal("slow group reported wrong number of dropped value:", dropped)
+		}
+
+		if fast.Dropped() != 0 || slow.Dropped() != 900 {
+			t.Fatal("Dropped() doesn't match expected:", fast.Dropped(), slow.Dropped())
+		}
+	})
+
+	t.Run("MembersShareValues", func(t *testing.T) {
+		memberCount := 4
+		count := 10000
+
+		ring := NewGroupRing[int](count)
+		group := ring.Group("workers")
+
+		for i := 0; i < count; i++ {
+			ring.Push(i)
+		}
+
+		read := make([]atomic.Int32, count)
+		var wg sync.WaitGroup
+		wg.Add(memberCount)
+		for i := 0; i < memberCount; i++ {
+			go func() {
+				defer wg.Done()
+				for {
+					next, ok, _ := group.TryNext()
+					if !ok {
+						return
+					}
+					read[next].Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		for i := range read {
+			if read[i].Load() != 1 {
+				t.Fatalf("value %v read %v times", i, read[i].Load())
+			}
+		}
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		ring := NewGroupRing[int](10)
+		group := ring.Group("replay")
+
+		for i := 0; i < 5; i++ {
+			ring.Push(i)
+		}
+
+		_, first, _, _ := group.TryNextSeq()
+		group.TryNext()
+
+		if err := group.Seek(first); err != nil {
+			t.Fatal(err)
+		}
+		next, seq, ok, _ := group.TryNextSeq()
+		if !ok || next != 0 || seq != first {
+			t.Fatal("value read after Seek() doesn't match expected")
+		}
+
+		if err := group.Seek(first + 6); err != ErrSequenceUnavailable {
+			t.Fatal("Seek() to future sequence didn't fail")
+		}
+	})
+
+	t.Run("Poller", func(t *testing.T) {
+		ring := NewGroupRing[int](10)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		poller := NewPoller[int](ring.Group("indexer"),
+			WithPollingInterval[int](time.Millisecond),
+			WithPollingContext[int](ctx),
+		)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			ring.Push(42)
+		}()
+
+		next, done, _ := poller.Next()
+		if done || next != 42 {
+			t.Fatal("polled value doesn't match expected")
+		}
+	})
+
+	t.Run("PollerPushFromOtherGroup", func(t *testing.T) {
+		ring := NewGroupRing[int](10)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		poller := NewPoller[int](ring.Group("indexer"),
+			WithPollingInterval[int](time.Millisecond),
+			WithPollingContext[int](ctx),
+		)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			ring.Group("archiver").Push(42)
+		}()
+
+		next, done, _ := poller.Next()
+		if done || next != 42 {
+			t.Fatal("value pushed through another group wasn't polled")
+		}
+	})
+
+	t.Run("Waiter", func(t *testing.T) {
+		pushes := map[string]func(ring *GroupRing[int], v int){
+			"PushThroughRing": func(ring *GroupRing[int], v int) {
+				ring.Push(v)
+			},
+			"PushFromOtherGroup": func(ring *GroupRing[int], v int) {
+				ring.Group("archiver").Push(v)
+			},
+		}
+
+		for name, push := range pushes {
+			t.Run(name, func(t *testing.T) {
+				ring := NewGroupRing[int](10)
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				waiter := NewWaiter[int](ring.Group("indexer"), WithWaiterContext[int](ctx))
+
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					push(ring, 42)
+				}()
+
+				next, done, _ := waiter.Next()
+				if done || next != 42 {
+					t.Fatal("waiter wasn't woken up by push")
+				}
+			})
+		}
+	})
+
+	t.Run("WaiterMembers", func(t *testing.T) {
+		ring := NewGroupRing[int](100)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		var read atomic.Int64
+		var wg sync.WaitGroup
+		wg.Add(3)
+		for i := 0; i < 3; i++ {
+			waiter := NewWaiter[int](ring.Group("indexer"), WithWaiterContext[int](ctx))
+			go func() {
+				defer wg.Done()
+				for {
+					if _, done, _ := waiter.Next(); done {
+						return
+					}
+					if read.Add(1) == 10 {
+						cancel()
+					}
+				}
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		for i := 0; i < 10; i++ {
+			ring.Push(i)
+		}
+		wg.Wait()
+
+		if read.Load() != 10 {
+			t.Fatalf("members read %v values, expecting 10", read.Load())
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			t.Fatal("members didn't read all values before timeout")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package ringo
+
+// GrowthPolicy define when a growable ring buffer resizes its backing array.
+// Buffer size is doubled when pressure (dropped values and, for ManyToOne,
+// collisions) crosses GrowThreshold and optionally halved once reader keeps
+// up. Resizing never loses unread values.
+type GrowthPolicy struct {
+	// MaxSize is the maximum size of the buffer. It can't be smaller than
+	// initial size.
+	MaxSize int
+	// GrowThreshold is the number of dropped values and collisions since last
+	// resize after which size is doubled. Zero means buffer grows on first
+	// drop.
+	GrowThreshold int
+	// ShrinkAfter is the number of consecutive reads without drop after which
+	// size is halved, never below initial size. Zero disables shrinking.
+	ShrinkAfter int
+}
+
+// growth tracks pressure on a growable ring buffer. It is only used by reader.
+type growth struct {
+	policy  GrowthPolicy
+	minSize int
+	// Dropped values and collisions since last resize.
+	pressure int
+	// Consecutive reads without drop.
+	calm int
+}
+
+func newGrowth(size int, policy GrowthPolicy) *growth {
+	if policy.MaxSize < size {
+		panic("growth policy max size can't be smaller than ring buffer size")
+	}
+	if policy.GrowThreshold < 0 || policy.ShrinkAfter < 0 {
+		panic("growth policy thresholds can't be negative")
+	}
+
+	return &growth{policy: policy, minSize: size}
+}
+
+// observe records a read and returns new size of the buffer. Pending is the
+// number of unread values, a buffer is never shrunk below it.
+func (g *growth) observe(size int, pending uint64, pressure int) int {
+	if pressure > 0 {
+		g.calm = 0
+		g.pressure += pressure
+		if g.pressure < g.policy.GrowThreshold || size >= g.policy.MaxSize {
+			return size
+		}
+
+		g.pressure = 0
+		if size > g.policy.MaxSize/2 {
+			return g.policy.MaxSize
+		}
+		return 2 * size
+	}
+
+	if g.policy.ShrinkAfter == 0 {
+		return size
+	}
+
+	g.calm++
+	if g.calm < g.policy.ShrinkAfter {
+		return size
+	}
+
+	g.calm = 0
+	half := size / 2
+	if half < g.minSize {
+		half = g.minSize
+	}
+	if half == size || pending > uint64(half) {
+		return size
+	}
+
+	g.pressure = 0
+	return half
+}
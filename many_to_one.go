@@ -2,6 +2,7 @@ package ringo
 
 import (
 	"math"
+	"runtime"
 	"sync/atomic"
 )
 
@@ -15,6 +16,19 @@ type ManyToOne[T any] struct {
 	// Also atomic as we read it on Push().
 	readIndex        atomic.Uint64
 	collisionHandler CollisionHandler
+	replay           bool
+
+	// Growth state, nil if buffer isn't growable. Backing array is swapped
+	// by reader once all writers left it (see enter and resize).
+	growth     *growth
+	size       atomic.Int64
+	active     atomic.Int64
+	resizing   atomic.Bool
+	collisions atomic.Uint64
+	// Collisions already accounted by growth, only used by reader.
+	observedCollisions uint64
+	// Values skipped by last resize, reported on next read.
+	resizeDropped int
 }
 
 type ManyToOneOption[T any] func(*ManyToOne[T])
@@ -27,6 +41,25 @@ func WithManyToOneCollisionHandler[T any](ch CollisionHandler) ManyToOneOption[T
 	}
 }
 
+// WithManyToOneReplay makes ManyToOne ring buffer keep read values until
+// they're overwritten so they can be read again using Seek.
+func WithManyToOneReplay[T any]() ManyToOneOption[T] {
+	return func(mto *ManyToOne[T]) {
+		mto.replay = true
+	}
+}
+
+// WithManyToOneGrowth makes ManyToOne grow (and optionally shrink) its backing
+// array according to the given policy. Pressure (drops and collisions) is
+// measured on TryNext and resizing is done by reader while writers wait. This
+// adds two atomic operations to every Push.
+func WithManyToOneGrowth[T any](policy GrowthPolicy) ManyToOneOption[T] {
+	return func(mto *ManyToOne[T]) {
+		mto.growth = newGrowth(len(mto.buffer), policy)
+		mto.size.Store(int64(len(mto.buffer)))
+	}
+}
+
 // NewManyToOne return a new ManyToOne ring buffer with the given
 // size. The buffer is safe for one reader and multiple writer.
 func NewManyToOne[T any](size int, options ...ManyToOneOption[T]) *ManyToOne[T] {
@@ -51,41 +84,61 @@ func NewManyToOne[T any](size int, options ...ManyToOneOption[T]) *ManyToOne[T]
 
 // Size implements Buffer.
 func (mto *ManyToOne[T]) Size() int {
+	if mto.growth != nil {
+		return int(mto.size.Load())
+	}
+
 	return len(mto.buffer)
 }
 
 // Push implements Buffer.
 func (mto *ManyToOne[T]) Push(data T) {
-	for {
-		writeIndex := mto.writeIndex.Add(1)
-		index := writeIndex % uint64(mto.Size())
+	if mto.growth != nil {
+		mto.enter()
+		defer mto.active.Add(-1)
+	}
 
-		old := mto.buffer[index].Load()
-		if old != nil && old.index > writeIndex {
-			mto.collisionHandler.OnCollision(mto)
-			continue
-		}
+	pushBox(mto.buffer, &mto.writeIndex, data, mto.onCollision)
+}
 
-		box := box[T]{
-			index: writeIndex,
-			data:  data,
-		}
+func (mto *ManyToOne[T]) onCollision() {
+	if mto.growth != nil {
+		mto.collisions.Add(1)
+	}
+	mto.collisionHandler.OnCollision(mto)
+}
 
-		if !mto.buffer[index].CompareAndSwap(old, &box) {
-			mto.collisionHandler.OnCollision(mto)
-			continue
+// enter registers writer as active unless a resize is in progress. Either
+// writer sees resizing flag or reader sees writer as active.
+func (mto *ManyToOne[T]) enter() {
+	for {
+		schedYield(schedGrowthEnter)
+		mto.active.Add(1)
+		if !mto.resizing.Load() {
+			return
 		}
 
-		return
+		mto.active.Add(-1)
+		schedYield(schedGrowthWait)
+		runtime.Gosched()
 	}
 }
 
 // TryNext implements Buffer.
 func (mto *ManyToOne[T]) TryNext() (result T, ok bool, dropped int) {
+	result, _, ok, dropped = mto.TryNextSeq()
+	return
+}
+
+// TryNextSeq is like TryNext but also returns the sequence number of the read
+// value. Sequence numbers are increasing, consecutive values have consecutive
+// sequence numbers.
+func (mto *ManyToOne[T]) TryNextSeq() (result T, seq uint64, ok bool, dropped int) {
 	readIndex := mto.readIndex.Load()
-	index := readIndex % uint64(mto.Size())
+	index := readIndex % uint64(len(mto.buffer))
 	// Swap(nil) is tempting to allow garbage collection of box[T] but
 	// it breaks collision detection (CompareAndSwap(old, ...) call) of Push().
+	schedYield(schedTryNextLoad)
 	box := mto.buffer[index].Load()
 
 	if box == nil {
@@ -93,24 +146,112 @@ func (mto *ManyToOne[T]) TryNext() (result T, ok bool, dropped int) {
 	}
 
 	// already read
-	if box.index < readIndex {
+	if seqBefore(box.index, readIndex) {
 		return
 	}
 
 	// cell have been overwritten
-	if box.index > readIndex {
+	if seqBefore(readIndex, box.index) {
 		dropped = int(box.index - readIndex)
 		// move readIndex to catch up.
 		mto.readIndex.Store(box.index)
 	}
 
+	schedYield(schedTryNextAdvance)
 	mto.readIndex.Add(1)
 	data := box.data
+	dropped += mto.resizeDropped
+	mto.resizeDropped = 0
 
 	// Replace box.data with zeroed value to allow gc to collect box.data or
 	// its content.
-	var zeroT T
-	box.data = zeroT
+	if !mto.replay {
+		var zeroT T
+		box.data = zeroT
+	}
+
+	if mto.growth != nil {
+		mto.observe(dropped)
+	}
+
+	return data, box.index, true, dropped
+}
+
+// observe records pressure of a read and resizes buffer if needed.
+func (mto *ManyToOne[T]) observe(dropped int) {
+	collisions := mto.collisions.Load()
+	pressure := dropped + int(collisions-mto.observedCollisions)
+	mto.observedCollisions = collisions
+
+	// Concurrent writes only makes pending greater, resize checks it again
+	// once writers are stopped.
+	pending := mto.writeIndex.Load() + 1 - mto.readIndex.Load()
+	if size := mto.growth.observe(len(mto.buffer), pending, pressure); size != len(mto.buffer) {
+		mto.resize(size)
+	}
+}
+
+// resize waits for writers to leave and moves most recent values to a new
+// backing array of the given size. Buffer isn't shrunk if unread values
+// doesn't fit anymore.
+func (mto *ManyToOne[T]) resize(size int) {
+	mto.resizing.Store(true)
+	defer mto.resizing.Store(false)
+
+	for mto.active.Load() != 0 {
+		schedYield(schedGrowthWait)
+		runtime.Gosched()
+	}
+
+	// Writers are stopped, counters are stable.
+	writeIndex := mto.writeIndex.Load()
+	if size < len(mto.buffer) && writeIndex+1-mto.readIndex.Load() > uint64(size) {
+		return
+	}
+
+	// Values older than the ones held by current backing array are lost, move
+	// reader past them as they won't be copied.
+	if held := uint64(len(mto.buffer)); writeIndex+1 > held {
+		oldest := writeIndex + 1 - held
+		if readIndex := mto.readIndex.Load(); readIndex < oldest {
+			mto.resizeDropped += int(oldest - readIndex)
+			mto.readIndex.Store(oldest)
+		}
+	}
+
+	buffer := make([]atomic.Pointer[box[T]], size)
+
+	// First increment overflows to 0, so writeIndex+1 is the number of writes.
+	count := writeIndex + 1
+	if count > uint64(size) {
+		count = uint64(size)
+	}
+	for seq := writeIndex + 1 - count; count > 0; seq, count = seq+1, count-1 {
+		box := mto.buffer[seq%uint64(len(mto.buffer))].Load()
+		if box != nil && box.index == seq {
+			buffer[seq%uint64(size)].Store(box)
+		}
+	}
+
+	mto.buffer = buffer
+	mto.size.Store(int64(size))
+}
+
+// Seek moves read cursor to the given sequence number so next read returns
+// value with this sequence number. Sequence must still be held in the buffer
+// or be the next one to be written, otherwise ErrSequenceUnavailable is
+// returned. Read values are held only if WithManyToOneReplay option is used.
+func (mto *ManyToOne[T]) Seek(seq uint64) error {
+	if seq != mto.writeIndex.Load()+1 {
+		box := mto.buffer[seq%uint64(len(mto.buffer))].Load()
+		if box == nil || box.index != seq {
+			return ErrSequenceUnavailable
+		}
+		if seqBefore(seq, mto.readIndex.Load()) && !mto.replay {
+			return ErrSequenceUnavailable
+		}
+	}
 
-	return data, true, dropped
+	mto.readIndex.Store(seq)
+	return nil
 }
@@ -1,7 +1,6 @@
 package ringo
 
 import (
-	"math/rand"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -9,138 +8,195 @@ import (
 )
 
 func TestManyToOne(t *testing.T) {
-	t.Run("SingleGoRoutine", func(t *testing.T) {
-		t.Run("SequentialReadWrite", func(t *testing.T) {
-			size := 1000
-			buffer := NewManyToOne[int](size)
-			for i := 0; i < size; i++ {
-				v := rand.Int()
-				buffer.Push(v)
-
-				r, ok, dropped := buffer.TryNext()
-				if !ok {
-					t.Fatal("TryNext() returned false, expecting true")
-				}
-				if r != v {
-					t.Fatal("value read from buffer doesn't match expected")
-				}
-				if dropped != 0 {
-					t.Fatal("buffer reported some dropped value")
-				}
+	t.Run("TryNextSeq", func(t *testing.T) {
+		buffer := NewManyToOne[int](10)
+
+		for i := 0; i < 30; i++ {
+			buffer.Push(i)
+		}
+
+		next, seq, ok, dropped := buffer.TryNextSeq()
+		if !ok {
+			t.Fatal("TryNextSeq() returned false, expecting true")
+		}
+		if next != 20 || dropped != 20 {
+			t.Fatal("value read from buffer doesn't match expected")
+		}
+
+		next2, seq2, _, _ := buffer.TryNextSeq()
+		if next2 != 21 || seq2 != seq+1 {
+			t.Fatal("sequence numbers aren't consecutive:", seq, seq2)
+		}
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		t.Run("Replay", func(t *testing.T) {
+			buffer := NewManyToOne(10, WithManyToOneReplay[int]())
+
+			for i := 0; i < 30; i++ {
+				buffer.Push(i)
 			}
-		})
 
-		t.Run("FullBufferThenEmptyIt", func(t *testing.T) {
-			size := 1000
-			buffer := NewManyToOne[int](size)
-			pushedData := make([]int, size)
+			_, first, _, _ := buffer.TryNextSeq()
+			for i := 21; i < 25; i++ {
+				buffer.TryNext()
+			}
 
-			for i := 0; i < size; i++ {
-				pushedData[i] = rand.Int()
-				buffer.Push(pushedData[i])
+			if err := buffer.Seek(first + 2); err != nil {
+				t.Fatal(err)
+			}
+			next, seq, ok, dropped := buffer.TryNextSeq()
+			if !ok || next != 22 || seq != first+2 || dropped != 0 {
+				t.Fatal("value read after Seek() doesn't match expected")
 			}
 
-			for i := 0; i < size; i++ {
-				r, ok, dropped := buffer.TryNext()
-				if !ok {
-					t.Fatal("TryNext() returned false, expecting true")
-				}
-				if dropped != 0 {
-					t.Fatal("buffer reported some dropped value:", dropped)
-				}
-				if r != pushedData[i] {
-					t.Fatal("value read from buffer doesn't match expected")
-				}
+			// Overwritten sequence.
+			if err := buffer.Seek(first - 1); err != ErrSequenceUnavailable {
+				t.Fatal("Seek() to overwritten sequence didn't fail")
+			}
+			// Future sequence.
+			if err := buffer.Seek(first + 11); err != ErrSequenceUnavailable {
+				t.Fatal("Seek() to future sequence didn't fail")
+			}
+			// End of buffer.
+			if err := buffer.Seek(first + 10); err != nil {
+				t.Fatal(err)
+			}
+			_, ok, _ = buffer.TryNext()
+			if ok {
+				t.Fatal("TryNext() returned true, expecting false")
 			}
 		})
-	})
 
-	t.Run("MultipleWriter", func(t *testing.T) {
-		writerCounter := 100
+		t.Run("WithoutReplay", func(t *testing.T) {
+			buffer := NewManyToOne[int](10)
 
-		size := 1000
-		buffer := NewManyToOne[int](size)
+			for i := 0; i < 5; i++ {
+				buffer.Push(i)
+			}
 
-		done := atomic.Int32{}
+			_, first, _, _ := buffer.TryNextSeq()
+			if err := buffer.Seek(first); err != ErrSequenceUnavailable {
+				t.Fatal("Seek() to read sequence without replay didn't fail")
+			}
 
-		for i := 0; i < writerCounter; i++ {
-			go func(i int) {
-				for j := 0; j < size; j++ {
-					buffer.Push(j)
-				}
+			// Skip unread values.
+			if err := buffer.Seek(first + 3); err != nil {
+				t.Fatal(err)
+			}
+			next, ok, _ := buffer.TryNext()
+			if !ok || next != 3 {
+				t.Fatal("value read after Seek() doesn't match expected")
+			}
+		})
+	})
 
-				done.Add(1)
-			}(i)
-		}
+	t.Run("Growth", func(t *testing.T) {
+		t.Run("Grow", func(t *testing.T) {
+			buffer := NewManyToOne[int](4, WithManyToOneGrowth[int](GrowthPolicy{MaxSize: 16}))
 
-		totalDropped := 0
-		totalRead := 0
+			for i := 0; i < 8; i++ {
+				buffer.Push(i)
+			}
 
-		// Read concurrently to writer.
-		for done.Load() != int32(writerCounter) {
-			r, ok, dropped := buffer.TryNext()
-			totalDropped += dropped
-			if ok {
-				totalRead++
-				if r < 0 || r > size {
-					t.Fatal("value read from buffer doesn't match expected")
+			next, ok, dropped := buffer.TryNext()
+			if !ok || next != 4 || dropped != 4 {
+				t.Fatal("value read from buffer doesn't match expected:", next, dropped)
+			}
+			if buffer.Size() != 8 {
+				t.Fatal("buffer didn't grow:", buffer.Size())
+			}
+			for i := 5; i < 8; i++ {
+				next, _, dropped := buffer.TryNext()
+				if next != i || dropped != 0 {
+					t.Fatal("value lost during resize")
 				}
 			}
-		}
 
-		// Read unread values.
-		for {
-			r, ok, dropped := buffer.TryNext()
-			totalDropped += dropped
-			if ok {
-				totalRead++
-				if r < 0 || r > size {
-					t.Fatal("value read from buffer doesn't match expected")
+			for i := 0; i < 8; i++ {
+				buffer.Push(i)
+			}
+			for i := 0; i < 8; i++ {
+				next, ok, dropped := buffer.TryNext()
+				if !ok || next != i || dropped != 0 {
+					t.Fatal("value read from grown buffer doesn't match expected")
 				}
-			} else {
-				break
 			}
-		}
+		})
 
-		if totalDropped+totalRead < writerCounter*size {
-			t.Fatalf("number of read and dropped value is less than expected, expected %v got %v", writerCounter*size, totalDropped+totalRead)
-		}
-	})
+		t.Run("ConcurrentWriters", func(t *testing.T) {
+			writerCount := 8
+			count := 10000
+			maxSize := 1024
 
-	t.Run("ReadEmptyBuffer", func(t *testing.T) {
-		size := 1000
-		buffer := NewManyToOne[int](size)
+			buffer := NewManyToOne[[2]int](4, WithManyToOneGrowth[[2]int](GrowthPolicy{
+				MaxSize:     maxSize,
+				ShrinkAfter: 64,
+			}), WithManyToOneCollisionHandler[[2]int](CollisionHandlerFunc(func(any) {})))
 
-		next, ok, dropped := buffer.TryNext()
-		if ok {
-			t.Fatal("TryNext() returned true, expecting false")
-		}
-		if dropped != 0 {
-			t.Fatal("buffer reported some dropped value:", dropped)
-		}
-		if next != 0 {
-			t.Fatal("value read from buffer doesn't match expected")
-		}
-	})
+			var wg sync.WaitGroup
+			wg.Add(writerCount)
+			for i := 0; i < writerCount; i++ {
+				go func(writer int) {
+					defer wg.Done()
+					for j := 0; j < count; j++ {
+						buffer.Push([2]int{writer, j})
+						if j%64 == 0 {
+							runtime.Gosched()
+						}
+					}
+				}(i)
+			}
 
-	t.Run("DroppedData", func(t *testing.T) {
-		size := 100
-		buffer := NewManyToOne[int](size)
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
 
-		for i := 0; i < 1000; i++ {
-			buffer.Push(i)
-		}
+			last := make([]int, writerCount)
+			for i := range last {
+				last[i] = -1
+			}
+			total := 0
+			read := func() bool {
+				next, ok, dropped := buffer.TryNext()
+				total += dropped
+				if !ok {
+					return false
+				}
+				total++
 
-		next, ok, dropped := buffer.TryNext()
-		if !ok {
-			t.Fatal("TryNext() returned true, expecting false")
-		}
-		if dropped != 900 {
-			t.Fatal("buffer reported some dropped value:", dropped)
-		}
-		if next != 900 {
-			t.Fatal("value read from buffer doesn't match expected")
-		}
+				// Values of a writer are read in order, exactly once.
+				if next[1] <= last[next[0]] {
+					t.Fatal("value read twice or out of order:", next)
+				}
+				last[next[0]] = next[1]
+				if buffer.Size() > maxSize {
+					t.Fatal("buffer size greater than max size:", buffer.Size())
+				}
+				return true
+			}
+
+		loop:
+			for {
+				select {
+				case <-done:
+					break loop
+				default:
+					if !read() {
+						runtime.Gosched()
+					}
+				}
+			}
+			for read() {
+			}
+
+			// Collisions waste sequence numbers that are reported as dropped.
+			if total < writerCount*count {
+				t.Fatalf("number of read and dropped value is less than expected, expected %v got %v", writerCount*count, total)
+			}
+		})
 	})
 
 	t.Run("CollisionDetection", func(t *testing.T) {
@@ -0,0 +1,211 @@
+package ringo
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+const (
+	messageHeaderSize = 4
+	// Header value marking the end of the buffer as unused, next message
+	// starts at the beginning of the buffer.
+	messagePadding = math.MaxUint32
+)
+
+var _ Buffer[[]byte] = &MessageRing{}
+
+// MessageRing define a ring buffer of variable-length messages stored in a
+// single contiguous byte buffer. Each message is prefixed with its length and
+// always stored contiguously, messages that doesn't fit at the end of the
+// buffer are moved to its beginning.
+//
+// By default, MessageRing is lossy: writers overwrite whole oldest messages
+// when the buffer is full. Use WithMessageRingBlocking to make writers wait for
+// the reader instead.
+//
+// MessageRing is safe for use by concurrent writers and a single reader.
+type MessageRing struct {
+	mu   sync.Mutex
+	cond sync.Cond
+
+	buffer []byte
+	// Offsets are total number of bytes written / read since creation, they
+	// never decrease.
+	writeOffset uint64
+	readOffset  uint64
+
+	blocking  bool
+	reserving bool
+	// End offset of pending reservation.
+	reservedEnd uint64
+	commitFn    func()
+
+	dropped int
+}
+
+// MessageRingOption can be used to setup the MessageRing.
+type MessageRingOption func(*MessageRing)
+
+// WithMessageRingBlocking makes MessageRing writers wait until the reader
+// frees enough space instead of overwriting oldest messages.
+func WithMessageRingBlocking() MessageRingOption {
+	return func(mr *MessageRing) {
+		mr.blocking = true
+	}
+}
+
+// NewMessageRing returns a new MessageRing backed by a byte buffer of the
+// given size. Size must be greater than the largest message plus its 4 bytes
+// length prefix.
+func NewMessageRing(size int, options ...MessageRingOption) *MessageRing {
+	if size <= messageHeaderSize {
+		panic("ring buffer size can't be smaller than message header")
+	}
+
+	mr := &MessageRing{
+		buffer: make([]byte, size),
+	}
+	mr.cond.L = &mr.mu
+	mr.commitFn = mr.commit
+
+	for _, opt := range options {
+		opt(mr)
+	}
+
+	return mr
+}
+
+// Size implements Buffer.
+func (mr *MessageRing) Size() int {
+	return len(mr.buffer)
+}
+
+// Push implements Buffer. Data is copied into the buffer.
+func (mr *MessageRing) Push(data []byte) {
+	buf, commit := mr.Reserve(len(data))
+	copy(buf, data)
+	commit()
+}
+
+// Reserve reserves space for a message of n bytes and returns a slice of the
+// internal buffer to write it. The message becomes visible to the reader once
+// commit is called. Reserve waits until previous reservation is committed, so
+// commit must always be called. Returned slice must not be used after commit.
+//
+// Reserve panics if message doesn't fit in the buffer.
+func (mr *MessageRing) Reserve(n int) (buf []byte, commit func()) {
+	size := uint64(len(mr.buffer))
+	if n < 0 || uint64(n) >= messagePadding || uint64(n)+messageHeaderSize > size {
+		panic("ringo: message doesn't fit in ring buffer")
+	}
+	need := uint64(n) + messageHeaderSize
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for mr.reserving {
+		mr.cond.Wait()
+	}
+	mr.reserving = true
+
+	start := mr.writeOffset
+	// Message doesn't fit before end of buffer, skip remaining bytes.
+	if pos := start % size; size-pos < need {
+		start += size - pos
+	}
+	end := start + need
+
+	for end-mr.readOffset > size {
+		if mr.readOffset == mr.writeOffset {
+			// Buffer is empty, move cursors to avoid padding.
+			mr.readOffset, mr.writeOffset = start, start
+			break
+		}
+
+		if mr.blocking {
+			mr.cond.Wait()
+			continue
+		}
+
+		mr.evict()
+	}
+
+	if start > mr.writeOffset {
+		pos := mr.writeOffset % size
+		if size-pos >= messageHeaderSize {
+			binary.LittleEndian.PutUint32(mr.buffer[pos:], messagePadding)
+		}
+	}
+
+	pos := start % size
+	binary.LittleEndian.PutUint32(mr.buffer[pos:], uint32(n))
+	mr.reservedEnd = end
+
+	return mr.buffer[pos+messageHeaderSize : pos+need], mr.commitFn
+}
+
+func (mr *MessageRing) commit() {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if !mr.reserving {
+		panic("ringo: commit called without pending reservation")
+	}
+
+	mr.writeOffset = mr.reservedEnd
+	mr.reserving = false
+	mr.cond.Broadcast()
+}
+
+// TryNext implements Buffer. Returned message is a copy of the one stored in
+// the buffer.
+func (mr *MessageRing) TryNext() (result []byte, ok bool, dropped int) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if mr.readOffset == mr.writeOffset {
+		return
+	}
+
+	offset, n := mr.next()
+	pos := offset % uint64(len(mr.buffer))
+	result = make([]byte, n)
+	copy(result, mr.buffer[pos+messageHeaderSize:])
+	mr.readOffset = offset + messageHeaderSize + n
+
+	dropped = mr.dropped
+	mr.dropped = 0
+
+	if mr.blocking {
+		mr.cond.Broadcast()
+	}
+
+	return result, true, dropped
+}
+
+// evict drops oldest message. Buffer must not be empty.
+func (mr *MessageRing) evict() {
+	offset, n := mr.next()
+	mr.readOffset = offset + messageHeaderSize + n
+	mr.dropped++
+}
+
+// next returns offset and length of next message to read, skipping padding.
+// Buffer must not be empty.
+func (mr *MessageRing) next() (offset uint64, n uint64) {
+	size := uint64(len(mr.buffer))
+	offset = mr.readOffset
+
+	pos := offset % size
+	if size-pos < messageHeaderSize {
+		offset += size - pos
+	} else if binary.LittleEndian.Uint32(mr.buffer[pos:]) == messagePadding {
+		offset += size - pos
+	}
+
+	pos = offset % size
+	n = uint64(binary.LittleEndian.Uint32(mr.buffer[pos:]))
+
+	return offset, n
+}
@@ -0,0 +1,228 @@
+package ringo
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func randomMessage(minSize, maxSize int) []byte {
+	msg := make([]byte, minSize+rand.Intn(maxSize-minSize+1))
+	_, _ = rand.Read(msg)
+	return msg
+}
+
+func TestMessageRing(t *testing.T) {
+	t.Run("SequentialReadWrite", func(t *testing.T) {
+		buffer := NewMessageRing(1024)
+		for i := 0; i < 1000; i++ {
+			msg := randomMessage(0, 200)
+			buffer.Push(msg)
+
+			r, ok, dropped := buffer.TryNext()
+			if !ok {
+				t.Fatal("TryNext() returned false, expecting true")
+			}
+			if !bytes.Equal(r, msg) {
+				t.Fatal("message read from buffer doesn't match expected")
+			}
+			if dropped != 0 {
+				t.Fatal("buffer reported some dropped value")
+			}
+		}
+	})
+
+	t.Run("WrapAround", func(t *testing.T) {
+		buffer := NewMessageRing(1000)
+		pushed := [][]byte{}
+
+		for i := 0; i < 10000; i++ {
+			msg := randomMessage(0, 300)
+			buffer.Push(msg)
+			pushed = append(pushed, msg)
+
+			// Keep at most 2 messages in buffer so nothing is dropped.
+			if len(pushed) == 2 {
+				for _, expected := range pushed {
+					r, ok, dropped := buffer.TryNext()
+					if !ok {
+						t.Fatal("TryNext() returned false, expecting true")
+					}
+					if dropped != 0 {
+						t.Fatal("buffer reported some dropped value:", dropped)
+					}
+					if !bytes.Equal(r, expected) {
+						t.Fatal("message read from buffer doesn't match expected")
+					}
+				}
+				pushed = pushed[:0]
+			}
+		}
+	})
+
+	t.Run("ReserveCommit", func(t *testing.T) {
+		buffer := NewMessageRing(64)
+
+		buf, commit := buffer.Reserve(5)
+		copy(buf, "hello")
+
+		_, ok, _ := buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true before commit, expecting false")
+		}
+
+		commit()
+
+		r, ok, _ := buffer.TryNext()
+		if !ok {
+			t.Fatal("TryNext() returned false, expecting true")
+		}
+		if string(r) != "hello" {
+			t.Fatal("message read from buffer doesn't match expected")
+		}
+	})
+
+	t.Run("ReadEmptyBuffer", func(t *testing.T) {
+		buffer := NewMessageRing(1000)
+
+		next, ok, dropped := buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true, expecting false")
+		}
+		if dropped != 0 {
+			t.Fatal("buffer reported some dropped value:", dropped)
+		}
+		if next != nil {
+			t.Fatal("message read from buffer doesn't match expected")
+		}
+	})
+
+	t.Run("DroppedData", func(t *testing.T) {
+		// 10 messages of 6 bytes (4 bytes header + 2 bytes payload).
+		buffer := NewMessageRing(60)
+
+		for i := 0; i < 100; i++ {
+			buffer.Push([]byte{byte(i), byte(i)})
+		}
+
+		next, ok, dropped := buffer.TryNext()
+		if !ok {
+			t.Fatal("TryNext() returned false, expecting true")
+		}
+		if dropped != 90 {
+			t.Fatal("buffer reported wrong number of dropped value:", dropped)
+		}
+		if next[0] != 90 {
+			t.Fatal("message read from buffer doesn't match expected")
+		}
+	})
+
+	t.Run("DroppedDataWrapAround", func(t *testing.T) {
+		buffer := NewMessageRing(1000)
+
+		totalRead := 0
+		totalDropped := 0
+		for i := 0; i < 10000; i++ {
+			buffer.Push(randomMessage(1, 300))
+			if i%10 == 0 {
+				_, ok, dropped := buffer.TryNext()
+				if !ok {
+					t.Fatal("TryNext() returned false, expecting true")
+				}
+				totalRead++
+				totalDropped += dropped
+			}
+		}
+
+		for {
+			_, ok, dropped := buffer.TryNext()
+			totalDropped += dropped
+			if !ok {
+				break
+			}
+			totalRead++
+		}
+
+		if totalRead+totalDropped != 10000 {
+			t.Fatalf("number of read and dropped messages doesn't match expected, expected %v got %v", 10000, totalRead+totalDropped)
+		}
+	})
+
+	t.Run("MessageTooLarge", func(t *testing.T) {
+		buffer := NewMessageRing(64)
+
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Push() of message larger than buffer didn't panic")
+			}
+		}()
+
+		buffer.Push(make([]byte, 61))
+	})
+
+	t.Run("Blocking", func(t *testing.T) {
+		t.Run("WaitsForReader", func(t *testing.T) {
+			buffer := NewMessageRing(12, WithMessageRingBlocking())
+			buffer.Push([]byte("abcd"))
+
+			pushed := make(chan struct{})
+			go func() {
+				buffer.Push([]byte("efgh"))
+				close(pushed)
+			}()
+
+			select {
+			case <-pushed:
+				t.Fatal("Push() didn't wait for reader")
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			r, ok, _ := buffer.TryNext()
+			if !ok || string(r) != "abcd" {
+				t.Fatal("message read from buffer doesn't match expected")
+			}
+
+			<-pushed
+
+			r, ok, _ = buffer.TryNext()
+			if !ok || string(r) != "efgh" {
+				t.Fatal("message read from buffer doesn't match expected")
+			}
+		})
+
+		t.Run("MultipleWriter", func(t *testing.T) {
+			writerCount := 10
+			msgPerWriter := 1000
+			buffer := NewMessageRing(512, WithMessageRingBlocking())
+
+			var wg sync.WaitGroup
+			wg.Add(writerCount)
+			for i := 0; i < writerCount; i++ {
+				go func() {
+					defer wg.Done()
+					for j := 0; j < msgPerWriter; j++ {
+						buffer.Push(randomMessage(1, 64))
+					}
+				}()
+			}
+
+			totalRead := 0
+			for totalRead < writerCount*msgPerWriter {
+				_, ok, dropped := buffer.TryNext()
+				if dropped != 0 {
+					t.Fatal("blocking buffer reported some dropped value:", dropped)
+				}
+				if ok {
+					totalRead++
+				} else {
+					runtime.Gosched()
+				}
+			}
+
+			wg.Wait()
+		})
+	})
+}
@@ -0,0 +1,277 @@
+package ringo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorPolicy define how a Pool handles errors returned by its handler.
+type ErrorPolicy int
+
+const (
+	// IgnoreErrors counts failed values and moves on to the next one.
+	IgnoreErrors ErrorPolicy = iota
+	// RetryErrors retries failed values with an exponential backoff, see
+	// WithPoolRetry.
+	RetryErrors
+	// StopOnError stops the pool on first error. Remaining values are left in
+	// the buffer, except the one being dispatched which is discarded.
+	StopOnError
+)
+
+// PoolStats define statistics of a Pool.
+type PoolStats struct {
+	// Values being handled by workers.
+	InFlight int64
+	// Values successfully handled.
+	Completed uint64
+	// Values whose handling failed, after retries.
+	Failed uint64
+	// Retried handler calls.
+	Retried uint64
+	// Values overwritten in the buffer before being dispatched.
+	Dropped uint64
+}
+
+// Pool define a pool of workers handling values read from a Buffer. Buffers
+// support a single reader, so values are read by a dispatcher go-routine and
+// handed to idle workers. Dispatcher polls the buffer when it is empty.
+type Pool[T any] struct {
+	buffer   Buffer[T]
+	handler  func(context.Context, T) error
+	interval time.Duration
+	policy   ErrorPolicy
+	attempts int
+	backoff  time.Duration
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	items     chan T
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mu  sync.Mutex
+	err error
+
+	inFlight  atomic.Int64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	retried   atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// PoolOption can be used to setup the Pool.
+type PoolOption[T any] func(*Pool[T])
+
+// WithPoolContext sets context of the pool. It is passed to handler and once
+// it is done, pool stops without draining the buffer. Default is
+// context.Background().
+func WithPoolContext[T any](ctx context.Context) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.ctx = ctx
+	}
+}
+
+// WithPoolPollingInterval sets the interval at which an empty buffer is
+// queried for new data. The default is 10ms.
+func WithPoolPollingInterval[T any](interval time.Duration) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.interval = interval
+	}
+}
+
+// WithPoolErrorPolicy sets error policy of the pool. Default is IgnoreErrors.
+func WithPoolErrorPolicy[T any](policy ErrorPolicy) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.policy = policy
+	}
+}
+
+// WithPoolRetry sets RetryErrors policy: a value is handled at most attempts
+// times, waiting backoff before first retry and doubling it on every retry.
+// Default is 3 attempts and a 10ms backoff.
+func WithPoolRetry[T any](attempts int, backoff time.Duration) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.policy = RetryErrors
+		p.attempts = attempts
+		p.backoff = backoff
+	}
+}
+
+// NewPool returns a new Pool of the given number of workers calling handler
+// on values read from buffer. Workers are started immediately.
+func NewPool[T any](buffer Buffer[T], workers int, handler func(context.Context, T) error, options ...PoolOption[T]) *Pool[T] {
+	if workers <= 0 {
+		panic("pool workers can't be negative or zero")
+	}
+
+	p := &Pool[T]{
+		buffer:   buffer,
+		handler:  handler,
+		interval: 10 * time.Millisecond,
+		attempts: 3,
+		backoff:  10 * time.Millisecond,
+		ctx:      context.Background(),
+		items:    make(chan T),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	p.ctx, p.cancel = context.WithCancel(p.ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			p.work()
+		}()
+	}
+	go p.dispatch()
+
+	go func() {
+		wg.Wait()
+		p.cancel()
+		close(p.done)
+	}()
+
+	return p
+}
+
+// Close stops the pool gracefully: values remaining in the buffer are
+// dispatched and Close waits for workers to handle them. Producers should be
+// stopped before. It returns the error that stopped the pool, if any.
+func (p *Pool[T]) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.closing)
+	})
+	<-p.done
+
+	return p.Err()
+}
+
+// Done returns a channel closed once every worker stopped.
+func (p *Pool[T]) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error that stopped the pool under StopOnError policy, if
+// any.
+func (p *Pool[T]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// Stats returns pool statistics.
+func (p *Pool[T]) Stats() PoolStats {
+	return PoolStats{
+		InFlight:  p.inFlight.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Retried:   p.retried.Load(),
+		Dropped:   p.dropped.Load(),
+	}
+}
+
+func (p *Pool[T]) dispatch() {
+	defer close(p.items)
+
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	for {
+		item, ok, dropped := p.buffer.TryNext()
+		if dropped > 0 {
+			p.dropped.Add(uint64(dropped))
+		}
+
+		if ok {
+			select {
+			case p.items <- item:
+			case <-p.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		// Buffer is drained.
+		select {
+		case <-p.closing:
+			return
+		default:
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(p.interval)
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.closing:
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *Pool[T]) work() {
+	for item := range p.items {
+		p.inFlight.Add(1)
+		err := p.handle(item)
+		p.inFlight.Add(-1)
+
+		if err == nil {
+			p.completed.Add(1)
+			continue
+		}
+
+		p.failed.Add(1)
+		if p.policy == StopOnError {
+			p.stop(err)
+		}
+	}
+}
+
+// handle calls handler, retrying according to error policy.
+func (p *Pool[T]) handle(item T) error {
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		err := p.handler(p.ctx, item)
+		if err == nil || p.policy != RetryErrors || attempt >= p.attempts {
+			return err
+		}
+
+		p.retried.Add(1)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-p.ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (p *Pool[T]) stop(err error) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+
+	p.cancel()
+}
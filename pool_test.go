@@ -0,0 +1,231 @@
+package ringo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("HandleAll", func(t *testing.T) {
+		buffer := NewManyToOne[int](1000)
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i)
+		}
+
+		var mu sync.Mutex
+		handled := make(map[int]bool)
+		pool := NewPool[int](buffer, 4, func(_ context.Context, v int) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if handled[v] {
+				t.Error("value handled twice:", v)
+			}
+			handled[v] = true
+			return nil
+		}, WithPoolPollingInterval[int](time.Millisecond))
+
+		if err := pool.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(handled) != 1000 {
+			t.Fatalf("%v values handled, expecting 1000", len(handled))
+		}
+		stats := pool.Stats()
+		if stats.Completed != 1000 || stats.Failed != 0 || stats.InFlight != 0 || stats.Dropped != 0 {
+			t.Fatal("stats doesn't match expected:", stats)
+		}
+	})
+
+	t.Run("ConcurrentWorkers", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		workers := 4
+
+		var inFlight, maxInFlight atomic.Int64
+		release := make(chan struct{})
+		pool := NewPool[int](buffer, workers, func(_ context.Context, v int) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				prev := maxInFlight.Load()
+				if n <= prev || maxInFlight.CompareAndSwap(prev, n) {
+					break
+				}
+			}
+
+			<-release
+			return nil
+		}, WithPoolPollingInterval[int](time.Millisecond))
+
+		for i := 0; i < 10; i++ {
+			buffer.Push(i)
+		}
+		for i := 0; pool.Stats().InFlight != int64(workers); i++ {
+			if i == 1000 {
+				t.Fatal("workers aren't handling values concurrently:", pool.Stats().InFlight)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+
+		if err := pool.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if maxInFlight.Load() != int64(workers) {
+			t.Fatal("unexpected number of concurrent handlers:", maxInFlight.Load())
+		}
+		if pool.Stats().Completed != 10 {
+			t.Fatal("not all values were handled:", pool.Stats())
+		}
+	})
+
+	t.Run("IgnoreErrors", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		for i := 0; i < 10; i++ {
+			buffer.Push(i)
+		}
+
+		pool := NewPool[int](buffer, 2, func(_ context.Context, v int) error {
+			if v%2 == 0 {
+				return errors.New("even value")
+			}
+			return nil
+		})
+
+		if err := pool.Close(); err != nil {
+			t.Fatal("Close() returned an ignored error:", err)
+		}
+		stats := pool.Stats()
+		if stats.Completed != 5 || stats.Failed != 5 {
+			t.Fatal("stats doesn't match expected:", stats)
+		}
+	})
+
+	t.Run("RetryErrors", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		buffer.Push(1)
+		buffer.Push(2)
+
+		var calls sync.Map
+		pool := NewPool[int](buffer, 2, func(_ context.Context, v int) error {
+			n, _ := calls.LoadOrStore(v, new(atomic.Int32))
+			attempt := n.(*atomic.Int32).Add(1)
+
+			// Value 1 succeeds on third attempt, value 2 always fails.
+			if v == 1 && attempt == 3 {
+				return nil
+			}
+			return errors.New("transient error")
+		}, WithPoolRetry[int](3, time.Millisecond))
+
+		if err := pool.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		stats := pool.Stats()
+		if stats.Completed != 1 || stats.Failed != 1 || stats.Retried != 4 {
+			t.Fatal("stats doesn't match expected:", stats)
+		}
+	})
+
+	t.Run("StopOnError", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		for i := 0; i < 10; i++ {
+			buffer.Push(i)
+		}
+
+		errStop := errors.New("stop")
+		pool := NewPool[int](buffer, 1, func(_ context.Context, v int) error {
+			if v == 3 {
+				return errStop
+			}
+			return nil
+		}, WithPoolErrorPolicy[int](StopOnError))
+
+		<-pool.Done()
+		if err := pool.Close(); err != errStop {
+			t.Fatal("Close() didn't return handler error:", err)
+		}
+
+		stats := pool.Stats()
+		if stats.Completed+stats.Failed == 10 || stats.Failed != 1 {
+			t.Fatal("pool didn't stop on error:", stats)
+		}
+		if _, ok, _ := buffer.TryNext(); !ok {
+			t.Fatal("remaining values were consumed")
+		}
+	})
+
+	t.Run("GracefulShutdown", func(t *testing.T) {
+		buffer := NewManyToOne[int](1000)
+		var handled atomic.Int64
+		pool := NewPool[int](buffer, 4, func(_ context.Context, v int) error {
+			time.Sleep(time.Microsecond)
+			handled.Add(1)
+			return nil
+		})
+
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i)
+		}
+		if err := pool.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if handled.Load() != 1000 {
+			t.Fatalf("%v values handled before shutdown, expecting 1000", handled.Load())
+		}
+		if _, ok, _ := buffer.TryNext(); ok {
+			t.Fatal("buffer wasn't drained")
+		}
+	})
+
+	t.Run("ContextCancellation", func(t *testing.T) {
+		buffer := NewManyToOne[int](100)
+		for i := 0; i < 10; i++ {
+			buffer.Push(i)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		pool := NewPool[int](buffer, 1, func(ctx context.Context, v int) error {
+			if v == 0 {
+				cancel()
+			}
+			<-ctx.Done()
+			return nil
+		}, WithPoolContext[int](ctx))
+
+		select {
+		case <-pool.Done():
+		case <-time.After(time.Second):
+			t.Fatal("pool didn't stop on context cancellation")
+		}
+		if pool.Stats().Completed == 10 {
+			t.Fatal("pool drained buffer after context cancellation")
+		}
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		buffer := NewManyToOne[int](10)
+		for i := 0; i < 100; i++ {
+			buffer.Push(i)
+		}
+
+		pool := NewPool[int](buffer, 1, func(context.Context, int) error {
+			return nil
+		})
+		if err := pool.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		stats := pool.Stats()
+		if stats.Dropped != 90 || stats.Completed != 10 {
+			t.Fatal("stats doesn't match expected:", stats)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package ringo
+
+import "sync/atomic"
+
+// Priority define a ring buffer made of multiple lanes, one per priority.
+// Each lane is a ManyToOne ring buffer, so values of a lane are never
+// overwritten by values of other lanes. Reader always drains higher lanes
+// first unless anti-starvation weights are set (see WithPriorityWeights).
+//
+// Priority is safe for use by concurrent writers and a single reader.
+type Priority[T any] struct {
+	lanes   []*ManyToOne[T]
+	dropped []atomic.Uint64
+	// Reader only.
+	weights []int
+	credits []int
+}
+
+// PriorityOption can be used to setup the Priority ring buffer.
+type PriorityOption[T any] func(*Priority[T])
+
+// WithPriorityWeights sets anti-starvation weights of lanes, one per lane
+// starting from lowest priority. A lane is read at most weight times before
+// lower lanes with pending values are read. For example, with weights 1 and 4,
+// 4 values of lane 1 are read for every value of lane 0 when both have pending
+// values.
+func WithPriorityWeights[T any](weights ...int) PriorityOption[T] {
+	return func(p *Priority[T]) {
+		if len(weights) != len(p.lanes) {
+			panic("number of weights doesn't match number of lanes")
+		}
+		for _, w := range weights {
+			if w <= 0 {
+				panic("priority weight can't be negative or zero")
+			}
+		}
+
+		p.weights = weights
+		p.credits = append([]int{}, weights...)
+	}
+}
+
+// WithPriorityLaneOptions sets options of ManyToOne lanes.
+func WithPriorityLaneOptions[T any](options ...ManyToOneOption[T]) PriorityOption[T] {
+	return func(p *Priority[T]) {
+		for i := range p.lanes {
+			for _, opt := range options {
+				opt(p.lanes[i])
+			}
+		}
+	}
+}
+
+// NewPriority returns a new Priority ring buffer with the given number of
+// lanes, each of the given size. Lane 0 has the lowest priority.
+func NewPriority[T any](lanes, size int, options ...PriorityOption[T]) *Priority[T] {
+	if lanes <= 0 {
+		panic("number of lanes can't be negative or zero")
+	}
+
+	p := &Priority[T]{
+		lanes:   make([]*ManyToOne[T], lanes),
+		dropped: make([]atomic.Uint64, lanes),
+	}
+	for i := range p.lanes {
+		p.lanes[i] = NewManyToOne[T](size)
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	return p
+}
+
+// Lanes returns number of lanes.
+func (p *Priority[T]) Lanes() int {
+	return len(p.lanes)
+}
+
+// Size returns size of all lanes.
+func (p *Priority[T]) Size() int {
+	return len(p.lanes) * p.lanes[0].Size()
+}
+
+// Push data to lane with the given priority.
+func (p *Priority[T]) Push(priority int, data T) {
+	p.lanes[priority].Push(data)
+}
+
+// TryNext reads value from highest lane with pending values, taking weights
+// into account. Returned boolean is true if a value was successfully read. Int
+// correspond to the number of dropped value of the lane since its last read.
+func (p *Priority[T]) TryNext() (result T, ok bool, dropped int) {
+	result, _, ok, dropped = p.TryNextLane()
+	return
+}
+
+// TryNextLane is like TryNext but also returns the lane of the read value.
+func (p *Priority[T]) TryNextLane() (result T, lane int, ok bool, dropped int) {
+	for {
+		skipped := false
+
+		for lane = len(p.lanes) - 1; lane >= 0; lane-- {
+			if p.weights != nil && p.credits[lane] == 0 {
+				skipped = true
+				continue
+			}
+
+			result, ok, dropped = p.lanes[lane].TryNext()
+			if !ok {
+				continue
+			}
+
+			if dropped > 0 {
+				p.dropped[lane].Add(uint64(dropped))
+			}
+			if p.weights != nil {
+				p.credits[lane]--
+			}
+
+			return
+		}
+
+		if !skipped {
+			return
+		}
+
+		// Lanes with credits are empty.
+		copy(p.credits, p.weights)
+	}
+}
+
+// Dropped returns total number of values dropped by the given lane.
+func (p *Priority[T]) Dropped(lane int) uint64 {
+	return p.dropped[lane].Load()
+}
@@ -0,0 +1,116 @@
+package ringo
+
+import (
+	"testing"
+)
+
+func TestPriority(t *testing.T) {
+	t.Run("HigherLanesFirst", func(t *testing.T) {
+		buffer := NewPriority[int](3, 100)
+
+		for i := 0; i < 10; i++ {
+			buffer.Push(i%3, i)
+		}
+
+		lastLane := 2
+		for i := 0; i < 10; i++ {
+			next, lane, ok, dropped := buffer.TryNextLane()
+			if !ok {
+				t.Fatal("TryNextLane() returned false, expecting true")
+			}
+			if dropped != 0 {
+				t.Fatal("buffer reported some dropped value:", dropped)
+			}
+			if next%3 != lane {
+				t.Fatal("value read from wrong lane")
+			}
+			if lane > lastLane {
+				t.Fatal("lower lane read before higher lane")
+			}
+			lastLane = lane
+		}
+
+		_, ok, _ := buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true, expecting false")
+		}
+	})
+
+	t.Run("CriticalNotOverwritten", func(t *testing.T) {
+		buffer := NewPriority[string](2, 10)
+
+		buffer.Push(1, "critical")
+		for i := 0; i < 1000; i++ {
+			buffer.Push(0, "debug")
+		}
+
+		next, lane, ok, dropped := buffer.TryNextLane()
+		if !ok || next != "critical" || lane != 1 || dropped != 0 {
+			t.Fatal("critical value was overwritten")
+		}
+
+		_, lane, _, dropped = buffer.TryNextLane()
+		if lane != 0 || dropped != 990 {
+			t.Fatal("debug lane reported wrong number of dropped value:", dropped)
+		}
+
+		if buffer.Dropped(0) != 990 || buffer.Dropped(1) != 0 {
+			t.Fatal("Dropped() doesn't match expected:", buffer.Dropped(0), buffer.Dropped(1))
+		}
+	})
+
+	t.Run("Weights", func(t *testing.T) {
+		buffer := NewPriority(2, 100, WithPriorityWeights[int](1, 4))
+
+		for i := 0; i < 50; i++ {
+			buffer.Push(0, i)
+			buffer.Push(1, i)
+		}
+
+		// 4 values of lane 1 for every value of lane 0.
+		counts := [2]int{}
+		for i := 0; i < 50; i++ {
+			_, lane, ok, _ := buffer.TryNextLane()
+			if !ok {
+				t.Fatal("TryNextLane() returned false, expecting true")
+			}
+			counts[lane]++
+		}
+		if counts[0] != 10 || counts[1] != 40 {
+			t.Fatal("lanes weren't read according to weights:", counts)
+		}
+
+		// Lane 1 is drained when lane 0 is empty.
+		for {
+			_, ok, _ := buffer.TryNext()
+			if !ok {
+				break
+			}
+		}
+		buffer.Push(1, 1)
+		buffer.Push(1, 2)
+		for i := 0; i < 2; i++ {
+			_, lane, ok, _ := buffer.TryNextLane()
+			if !ok || lane != 1 {
+				t.Fatal("lane with exhausted weight wasn't read while other lanes are empty")
+			}
+		}
+	})
+
+	t.Run("InvalidWeights", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("NewPriority() with invalid weights didn't panic")
+			}
+		}()
+
+		NewPriority(2, 100, WithPriorityWeights[int](1))
+	})
+
+	t.Run("Size", func(t *testing.T) {
+		buffer := NewPriority[int](3, 100)
+		if buffer.Size() != 300 || buffer.Lanes() != 3 {
+			t.Fatal("size doesn't match expected:", buffer.Size())
+		}
+	})
+}
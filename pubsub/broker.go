@@ -0,0 +1,217 @@
+// Package pubsub provides an in-process publish/subscribe broker built on
+// ringo buffers. Each subscriber has its own lossy ring, so a slow subscriber
+// drops messages instead of blocking publishers.
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/negrel/ringo"
+)
+
+// Message define a message published to a topic.
+type Message[T any] struct {
+	Topic string
+	Data  T
+}
+
+// Broker define a publish/subscribe broker. Topics are dot separated tokens
+// (e.g. "orders.eu.created") and subscription patterns may use NATS style
+// wildcards: '*' matches a single token and a trailing '>' matches one or
+// more tokens (e.g. "orders.*.created" or "orders.>").
+//
+// Broker is safe for concurrent use. Publish is lock free: subscriptions are
+// stored in a copy-on-write list.
+type Broker[T any] struct {
+	size int
+
+	mu   sync.Mutex
+	subs atomic.Pointer[[]*Subscription[T]]
+}
+
+// Option can be used to setup the Broker.
+type Option[T any] func(*Broker[T])
+
+// WithSubscriberSize sets default size of subscribers ring buffers. Default is
+// 1024.
+func WithSubscriberSize[T any](size int) Option[T] {
+	return func(b *Broker[T]) {
+		b.size = size
+	}
+}
+
+// NewBroker returns a new Broker.
+func NewBroker[T any](options ...Option[T]) *Broker[T] {
+	b := &Broker[T]{size: 1024}
+	b.subs.Store(&[]*Subscription[T]{})
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
+}
+
+// Publish publishes data to the given topic. Data is pushed to ring buffer of
+// every matching subscription, publishers are never blocked by subscribers.
+func (b *Broker[T]) Publish(topic string, data T) error {
+	tokens, err := parseTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	msg := Message[T]{Topic: topic, Data: data}
+	for _, sub := range *b.subs.Load() {
+		if match(sub.tokens, tokens) {
+			sub.delivered.Add(1)
+			sub.waiter.Push(msg)
+		}
+	}
+
+	return nil
+}
+
+// SubscribeOption can be used to setup a Subscription.
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	size int
+}
+
+// WithSize sets size of subscription ring buffer. Default is broker
+// subscriber size.
+func WithSize(size int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.size = size
+	}
+}
+
+// Subscribe subscribes to topics matching the given pattern. Subscription is
+// cancelled once ctx is done or Unsubscribe is called.
+func (b *Broker[T]) Subscribe(ctx context.Context, pattern string, options ...SubscribeOption) (*Subscription[T], error) {
+	tokens, err := parsePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	config := subscribeConfig{size: b.size}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &Subscription[T]{
+		pattern: pattern,
+		tokens:  tokens,
+		buffer:  ringo.NewManyToOne[Message[T]](config.size),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	sub.waiter = ringo.NewWaiter[Message[T]](sub.buffer, ringo.WithWaiterContext[Message[T]](ctx))
+
+	b.mu.Lock()
+	subs := append(append([]*Subscription[T]{}, *b.subs.Load()...), sub)
+	b.subs.Store(&subs)
+	b.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		b.remove(sub)
+	})
+
+	return sub, nil
+}
+
+func (b *Broker[T]) remove(sub *Subscription[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := make([]*Subscription[T], 0, len(*b.subs.Load()))
+	for _, s := range *b.subs.Load() {
+		if s != sub {
+			subs = append(subs, s)
+		}
+	}
+	b.subs.Store(&subs)
+}
+
+// Subscriptions returns number of active subscriptions.
+func (b *Broker[T]) Subscriptions() int {
+	return len(*b.subs.Load())
+}
+
+// Subscription define a subscription to topics matching a pattern. It must be
+// read by a single go-routine.
+type Subscription[T any] struct {
+	pattern string
+	tokens  []string
+	buffer  *ringo.ManyToOne[Message[T]]
+	waiter  ringo.Waiter[Message[T]]
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	delivered atomic.Uint64
+	received  atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// SubscriptionStats define statistics of a Subscription.
+type SubscriptionStats struct {
+	// Messages pushed to subscription ring buffer.
+	Delivered uint64
+	// Messages read by subscriber.
+	Received uint64
+	// Messages overwritten before being read.
+	Dropped uint64
+}
+
+// Pattern returns subscription pattern.
+func (s *Subscription[T]) Pattern() string {
+	return s.pattern
+}
+
+// Next waits for next message. Done is true if subscription is cancelled.
+// Dropped is the number of messages dropped since last read.
+func (s *Subscription[T]) Next() (msg Message[T], done bool, dropped int) {
+	msg, done, dropped = s.waiter.Next()
+	s.record(!done, dropped)
+	return
+}
+
+// TryNext reads next message if any. Returned boolean is true if a message
+// was successfully read. Int correspond to the number of dropped messages
+// since last read.
+func (s *Subscription[T]) TryNext() (msg Message[T], ok bool, dropped int) {
+	msg, ok, dropped = s.buffer.TryNext()
+	s.record(ok, dropped)
+	return
+}
+
+func (s *Subscription[T]) record(ok bool, dropped int) {
+	if ok {
+		s.received.Add(1)
+	}
+	if dropped > 0 {
+		s.dropped.Add(uint64(dropped))
+	}
+}
+
+// Stats returns subscription statistics.
+func (s *Subscription[T]) Stats() SubscriptionStats {
+	return SubscriptionStats{
+		Delivered: s.delivered.Load(),
+		Received:  s.received.Load(),
+		Dropped:   s.dropped.Load(),
+	}
+}
+
+// Unsubscribe cancels subscription. Pending Next calls return done.
+func (s *Subscription[T]) Unsubscribe() {
+	s.cancel()
+}
+
+// Done returns a channel closed once subscription is cancelled.
+func (s *Subscription[T]) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
@@ -0,0 +1,158 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBroker(t *testing.T) {
+	t.Run("PublishSubscribe", func(t *testing.T) {
+		broker := NewBroker[int]()
+		ctx := context.Background()
+
+		all, _ := broker.Subscribe(ctx, "orders.>")
+		created, _ := broker.Subscribe(ctx, "orders.*.created")
+		other, _ := broker.Subscribe(ctx, "users.>")
+
+		for i := 0; i < 10; i++ {
+			if err := broker.Publish("orders.eu.created", i); err != nil {
+				t.Fatal(err)
+			}
+		}
+		broker.Publish("orders.eu.deleted", 42)
+
+		for _, sub := range []*Subscription[int]{all, created} {
+			for i := 0; i < 10; i++ {
+				msg, done, dropped := sub.Next()
+				if done || dropped != 0 {
+					t.Fatal("Next() returned done or dropped messages")
+				}
+				if msg.Topic != "orders.eu.created" || msg.Data != i {
+					t.Fatal("message doesn't match expected:", msg)
+				}
+			}
+		}
+
+		msg, ok, _ := all.TryNext()
+		if !ok || msg.Data != 42 {
+			t.Fatal("message doesn't match expected:", msg)
+		}
+		if _, ok, _ := created.TryNext(); ok {
+			t.Fatal("subscription received a message of another topic")
+		}
+		if _, ok, _ := other.TryNext(); ok {
+			t.Fatal("subscription received a message of another topic")
+		}
+	})
+
+	t.Run("InvalidPatternOrTopic", func(t *testing.T) {
+		broker := NewBroker[int]()
+		if _, err := broker.Subscribe(context.Background(), "orders.>.eu"); err != ErrInvalidPattern {
+			t.Fatal("Subscribe() didn't fail")
+		}
+		if err := broker.Publish("orders.*", 1); err != ErrInvalidTopic {
+			t.Fatal("Publish() didn't fail")
+		}
+	})
+
+	t.Run("SlowSubscriber", func(t *testing.T) {
+		broker := NewBroker[int](WithSubscriberSize[int](100))
+		slow, _ := broker.Subscribe(context.Background(), "metrics")
+		fast, _ := broker.Subscribe(context.Background(), "metrics", WithSize(1000))
+
+		for i := 0; i < 1000; i++ {
+			broker.Publish("metrics", i)
+		}
+
+		msg, _, dropped := slow.Next()
+		if dropped != 900 || msg.Data != 900 {
+			t.Fatal("slow subscriber reported wrong number of dropped messages:", dropped)
+		}
+		msg, _, dropped = fast.Next()
+		if dropped != 0 || msg.Data != 0 {
+			t.Fatal("fast subscriber dropped messages:", dropped)
+		}
+
+		stats := slow.Stats()
+		if stats.Delivered != 1000 || stats.Received != 1 || stats.Dropped != 900 {
+			t.Fatal("stats doesn't match expected:", stats)
+		}
+	})
+
+	t.Run("ContextCancellation", func(t *testing.T) {
+		broker := NewBroker[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		sub, _ := broker.Subscribe(ctx, "orders.>")
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		_, done, _ := sub.Next()
+		if !done {
+			t.Fatal("Next() didn't return done after context cancellation")
+		}
+
+		<-sub.Done()
+		for i := 0; broker.Subscriptions() != 0; i++ {
+			if i == 100 {
+				t.Fatal("cancelled subscription wasn't removed")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		broker := NewBroker[int]()
+		sub, _ := broker.Subscribe(context.Background(), "orders.>")
+		sub.Unsubscribe()
+
+		if _, done, _ := sub.Next(); !done {
+			t.Fatal("Next() didn't return done after Unsubscribe()")
+		}
+	})
+
+	t.Run("ConcurrentPublishers", func(t *testing.T) {
+		publisherCount := 8
+		count := 1000
+
+		broker := NewBroker[int](WithSubscriberSize[int](publisherCount * count))
+		sub, _ := broker.Subscribe(context.Background(), "events.*")
+
+		var wg sync.WaitGroup
+		wg.Add(publisherCount)
+		for i := 0; i < publisherCount; i++ {
+			go func() {
+				defer wg.Done()
+				for j := 0; j < count; j++ {
+					broker.Publish("events.tick", j)
+				}
+			}()
+		}
+
+		// Subscribe and unsubscribe concurrently to publishers.
+		for i := 0; i < 100; i++ {
+			other, _ := broker.Subscribe(context.Background(), "events.>")
+			other.Unsubscribe()
+		}
+		wg.Wait()
+
+		received := 0
+		for {
+			_, ok, dropped := sub.TryNext()
+			if !ok {
+				break
+			}
+			if dropped != 0 {
+				t.Fatal("subscription dropped messages:", dropped)
+			}
+			received++
+		}
+		if received != publisherCount*count {
+			t.Fatalf("received %v messages, expected %v", received, publisherCount*count)
+		}
+	})
+}
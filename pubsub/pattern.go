@@ -0,0 +1,54 @@
+package pubsub
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidTopic is returned when publishing to an empty topic, a topic
+	// with empty tokens or wildcards.
+	ErrInvalidTopic = errors.New("pubsub: invalid topic")
+	// ErrInvalidPattern is returned when subscribing with an empty pattern,
+	// a pattern with empty tokens or a '>' wildcard that isn't last.
+	ErrInvalidPattern = errors.New("pubsub: invalid pattern")
+)
+
+// parseTopic splits topic into tokens.
+func parseTopic(topic string) ([]string, error) {
+	tokens := strings.Split(topic, ".")
+	for _, token := range tokens {
+		if token == "" || token == "*" || token == ">" {
+			return nil, ErrInvalidTopic
+		}
+	}
+
+	return tokens, nil
+}
+
+// parsePattern splits pattern into tokens.
+func parsePattern(pattern string) ([]string, error) {
+	tokens := strings.Split(pattern, ".")
+	for i, token := range tokens {
+		if token == "" || (token == ">" && i != len(tokens)-1) {
+			return nil, ErrInvalidPattern
+		}
+	}
+
+	return tokens, nil
+}
+
+// match returns true if topic tokens match pattern tokens. A '*' token matches
+// any single token and a trailing '>' token matches one or more tokens.
+func match(pattern, topic []string) bool {
+	for i, token := range pattern {
+		if token == ">" {
+			return len(topic) > i
+		}
+		if i >= len(topic) || (token != "*" && token != topic[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(topic)
+}
@@ -0,0 +1,56 @@
+package pubsub
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tcases := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.created", "orders.deleted", false},
+		{"orders.created", "orders.created.eu", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders", false},
+		{"orders.*", "orders.created.eu", false},
+		{"*.created", "orders.created", true},
+		{"orders.*.created", "orders.eu.created", true},
+		{"orders.>", "orders.created", true},
+		{"orders.>", "orders.eu.created", true},
+		{"orders.>", "orders", false},
+		{">", "orders", true},
+		{">", "orders.eu.created", true},
+		{"*.*.>", "orders.eu", false},
+		{"*.*.>", "orders.eu.created", true},
+	}
+
+	for _, tcase := range tcases {
+		pattern, err := parsePattern(tcase.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		topic, err := parseTopic(tcase.topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if match(pattern, topic) != tcase.match {
+			t.Fatalf("match(%q, %q) returned %v", tcase.pattern, tcase.topic, !tcase.match)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, pattern := range []string{"", ".", "orders.", "orders..created", "orders.>.created"} {
+		if _, err := parsePattern(pattern); err != ErrInvalidPattern {
+			t.Fatalf("parsePattern(%q) didn't fail", pattern)
+		}
+	}
+
+	for _, topic := range []string{"", "orders.", "orders.*", "orders.>"} {
+		if _, err := parseTopic(topic); err != ErrInvalidTopic {
+			t.Fatalf("parseTopic(%q) didn't fail", topic)
+		}
+	}
+}
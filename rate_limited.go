@@ -0,0 +1,96 @@
+package ringo
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ Buffer[any] = &RateLimited[any]{}
+
+// RateLimited define a Buffer decorator that rate limits Push using a token
+// bucket. Values pushed while the bucket is empty are suppressed.
+type RateLimited[T any] struct {
+	Buffer[T]
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	suppressed atomic.Uint64
+}
+
+// RateLimitedOption can be used to setup the RateLimited buffer.
+type RateLimitedOption[T any] func(*RateLimited[T])
+
+// WithRateLimitedClock sets clock used to refill the token bucket. Default is
+// time.Now.
+func WithRateLimitedClock[T any](now func() time.Time) RateLimitedOption[T] {
+	return func(rl *RateLimited[T]) {
+		rl.now = now
+	}
+}
+
+// NewRateLimited returns a new RateLimited buffer wrapping the given one. At
+// most rate values per second are pushed on average with bursts of at most
+// burst values. Bucket is initially full.
+func NewRateLimited[T any](buffer Buffer[T], rate float64, burst int, options ...RateLimitedOption[T]) *RateLimited[T] {
+	if rate <= 0 || burst <= 0 {
+		panic("rate limit can't be negative or zero")
+	}
+
+	rl := &RateLimited[T]{
+		Buffer: buffer,
+		rate:   rate,
+		burst:  float64(burst),
+		now:    time.Now,
+		tokens: float64(burst),
+	}
+
+	for _, opt := range options {
+		opt(rl)
+	}
+
+	rl.last = rl.now()
+
+	return rl
+}
+
+// Push implements Buffer. Data is suppressed if rate limit is exceeded.
+func (rl *RateLimited[T]) Push(data T) {
+	if !rl.take() {
+		rl.suppressed.Add(1)
+		return
+	}
+
+	rl.Buffer.Push(data)
+}
+
+func (rl *RateLimited[T]) take() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if elapsed := now.Sub(rl.last); elapsed > 0 {
+		rl.tokens += elapsed.Seconds() * rl.rate
+		if rl.tokens > rl.burst {
+			rl.tokens = rl.burst
+		}
+	}
+	rl.last = now
+
+	if rl.tokens < 1 {
+		return false
+	}
+
+	rl.tokens--
+	return true
+}
+
+// Suppressed returns total number of values suppressed by rate limiting.
+func (rl *RateLimited[T]) Suppressed() uint64 {
+	return rl.suppressed.Load()
+}
@@ -0,0 +1,74 @@
+package ringo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	newRateLimited := func(size int, rate float64, burst int) (*RateLimited[int], *fakeClock) {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		return NewRateLimited[int](NewRing[int](size), rate, burst, WithRateLimitedClock[int](clock.Now)), clock
+	}
+
+	t.Run("Burst", func(t *testing.T) {
+		buffer, _ := newRateLimited(100, 10, 5)
+		for i := 0; i < 20; i++ {
+			buffer.Push(i)
+		}
+
+		for i := 0; i < 5; i++ {
+			next, ok, dropped := buffer.TryNext()
+			if !ok || next != i || dropped != 0 {
+				t.Fatal("value read from buffer doesn't match expected")
+			}
+		}
+		if _, ok, _ := buffer.TryNext(); ok {
+			t.Fatal("rate limit exceeded")
+		}
+		if buffer.Suppressed() != 15 {
+			t.Fatal("Suppressed() returned", buffer.Suppressed())
+		}
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		buffer, clock := newRateLimited(100, 10, 5)
+		for i := 0; i < 5; i++ {
+			buffer.Push(i)
+		}
+
+		// 100ms refill a single token.
+		clock.Advance(100 * time.Millisecond)
+		buffer.Push(5)
+		buffer.Push(6)
+
+		// Bucket never holds more than burst tokens.
+		clock.Advance(time.Hour)
+		for i := 7; i < 20; i++ {
+			buffer.Push(i)
+		}
+
+		count := 0
+		for _, ok, _ := buffer.TryNext(); ok; _, ok, _ = buffer.TryNext() {
+			count++
+		}
+		if count != 11 || buffer.Suppressed() != 9 {
+			t.Fatalf("%v values pushed and %v suppressed, expecting 11 and 9", count, buffer.Suppressed())
+		}
+	})
+
+	t.Run("SeparateFromDropped", func(t *testing.T) {
+		buffer, _ := newRateLimited(10, 10, 50)
+		for i := 0; i < 100; i++ {
+			buffer.Push(i)
+		}
+
+		next, _, dropped := buffer.TryNext()
+		if next != 40 || dropped != 40 {
+			t.Fatal("wrapped buffer didn't report overwritten values:", next, dropped)
+		}
+		if buffer.Suppressed() != 50 {
+			t.Fatal("Suppressed() returned", buffer.Suppressed())
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package ringo
 
+import "errors"
+
+// ErrSequenceUnavailable is returned when seeking to a sequence number that
+// isn't held in the buffer anymore or hasn't been written yet.
+var ErrSequenceUnavailable = errors.New("ringo: sequence unavailable")
+
 // Buffer define common methods of ring buffers.
 type Buffer[T any] interface {
 	// Size returns size of internal buffer.
@@ -18,18 +24,38 @@ type Ring[T any] struct {
 	buffer     []box[T]
 	writeIndex uint64
 	readIndex  uint64
+	// Sequence 0 is written once write index wrapped.
+	wrapped bool
+	growth  *growth
+}
+
+// RingOption can be used to setup the Ring.
+type RingOption[T any] func(*Ring[T])
+
+// WithRingGrowth makes Ring grow (and optionally shrink) its backing array
+// according to the given policy. Pressure is measured on TryNext.
+func WithRingGrowth[T any](policy GrowthPolicy) RingOption[T] {
+	return func(r *Ring[T]) {
+		r.growth = newGrowth(r.Size(), policy)
+	}
 }
 
-func NewRing[T any](size int) *Ring[T] {
+func NewRing[T any](size int, options ...RingOption[T]) *Ring[T] {
 	if size <= 0 {
 		panic("ring buffer size can't be negative or zero")
 	}
 
-	return &Ring[T]{
+	r := &Ring[T]{
 		buffer:     make([]box[T], size),
 		writeIndex: 0,
 		readIndex:  1, // Makes first TryNext() return false if no write before.
 	}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
 }
 
 // Size implements Buffer.
@@ -40,6 +66,9 @@ func (r *Ring[T]) Size() int {
 // Push implements Buffer.
 func (r *Ring[T]) Push(data T) {
 	r.writeIndex++
+	if r.writeIndex == 0 {
+		r.wrapped = true
+	}
 	index := r.writeIndex % uint64(r.Size())
 
 	r.buffer[index] = box[T]{r.writeIndex, data}
@@ -47,21 +76,69 @@ func (r *Ring[T]) Push(data T) {
 
 // TryNext implements Buffer.
 func (r *Ring[T]) TryNext() (result T, ok bool, dropped int) {
+	result, _, ok, dropped = r.TryNextSeq()
+	return
+}
+
+// TryNextSeq is like TryNext but also returns the sequence number of the read
+// value. Sequence numbers are increasing, consecutive values have consecutive
+// sequence numbers.
+func (r *Ring[T]) TryNextSeq() (result T, seq uint64, ok bool, dropped int) {
 	index := r.readIndex % uint64(r.Size())
 	box := r.buffer[index]
 
 	// read index is ahead of write index.
-	if box.index < r.readIndex {
+	if seqBefore(box.index, r.readIndex) {
 		return
 	}
 
 	// writer is faster that reader and have overwritten data.
-	if box.index > r.readIndex {
+	if seqBefore(r.readIndex, box.index) {
 		dropped = int(box.index - r.readIndex)
 		r.readIndex = box.index
 	}
 
 	r.readIndex++
 
-	return box.data, true, dropped
+	if r.growth != nil {
+		pending := r.writeIndex + 1 - r.readIndex
+		if size := r.growth.observe(r.Size(), pending, dropped); size != r.Size() {
+			r.resize(size)
+		}
+	}
+
+	return box.data, box.index, true, dropped
+}
+
+// resize moves most recent values to a new backing array of the given size.
+// Size must be greater or equal to number of unread values.
+func (r *Ring[T]) resize(size int) {
+	buffer := make([]box[T], size)
+
+	// Sequence 0 is never written.
+	count := r.writeIndex
+	if count > uint64(size) {
+		count = uint64(size)
+	}
+	for seq := r.writeIndex - count + 1; count > 0; seq, count = seq+1, count-1 {
+		if box := r.buffer[seq%uint64(r.Size())]; box.index == seq {
+			buffer[seq%uint64(size)] = box
+		}
+	}
+
+	r.buffer = buffer
+}
+
+// Seek moves read cursor to the given sequence number so next read returns
+// value with this sequence number. Sequence must still be held in the buffer
+// or be the next one to be written, otherwise ErrSequenceUnavailable is
+// returned.
+func (r *Ring[T]) Seek(seq uint64) error {
+	// Sequence 0 is held by empty slots until write index wrapped.
+	if seq != r.writeIndex+1 && ((seq == 0 && !r.wrapped) || r.buffer[seq%uint64(r.Size())].index != seq) {
+		return ErrSequenceUnavailable
+	}
+
+	r.readIndex = seq
+	return nil
 }
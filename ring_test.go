@@ -1,88 +1,195 @@
 package ringo
 
 import (
-	"math/rand"
 	"testing"
 )
 
 func TestRing(t *testing.T) {
-	t.Run("SequentialReadWrite", func(t *testing.T) {
-		size := 1000
-		buffer := NewRing[int](size)
-		for i := 0; i < size; i++ {
-			v := rand.Int()
-			buffer.Push(v)
+	t.Run("TryNextSeq", func(t *testing.T) {
+		buffer := NewRing[int](10)
 
-			r, ok, dropped := buffer.TryNext()
-			if !ok {
-				t.Fatal("TryNext() returned false, expecting true")
-			}
-			if r != v {
-				t.Fatal("value read from buffer doesn't match expected")
-			}
-			if dropped != 0 {
-				t.Fatal("buffer reported some dropped value")
-			}
+		for i := 0; i < 30; i++ {
+			buffer.Push(i)
 		}
-	})
-
-	t.Run("FullBufferThenEmptyIt", func(t *testing.T) {
-		size := 1000
-		buffer := NewRing[int](size)
-		pushedData := make([]int, size)
 
-		for i := 0; i < size; i++ {
-			pushedData[i] = rand.Int()
-			buffer.Push(pushedData[i])
+		next, seq, ok, dropped := buffer.TryNextSeq()
+		if !ok {
+			t.Fatal("TryNextSeq() returned false, expecting true")
+		}
+		if next != 20 || dropped != 20 {
+			t.Fatal("value read from buffer doesn't match expected")
 		}
 
-		for i := 0; i < size; i++ {
-			r, ok, dropped := buffer.TryNext()
-			if !ok {
-				t.Fatal("TryNext() returned false, expecting true")
-			}
-			if dropped != 0 {
-				t.Fatal("buffer reported some dropped value:", dropped)
-			}
-			if r != pushedData[i] {
-				t.Fatal("value read from buffer doesn't match expected")
-			}
+		next2, seq2, _, _ := buffer.TryNextSeq()
+		if next2 != 21 || seq2 != seq+1 {
+			t.Fatal("sequence numbers aren't consecutive:", seq, seq2)
 		}
 	})
 
-	t.Run("ReadEmptyBuffer", func(t *testing.T) {
-		size := 1000
-		buffer := NewRing[int](size)
+	t.Run("Seek", func(t *testing.T) {
+		buffer := NewRing[int](10)
 
-		next, ok, dropped := buffer.TryNext()
-		if ok {
-			t.Fatal("TryNext() returned true, expecting false")
+		if err := buffer.Seek(0); err != ErrSequenceUnavailable {
+			t.Fatal("Seek() to unwritten sequence didn't fail")
 		}
-		if dropped != 0 {
-			t.Fatal("buffer reported some dropped value:", dropped)
+
+		for i := 0; i < 30; i++ {
+			buffer.Push(i)
 		}
-		if next != 0 {
-			t.Fatal("value read from buffer doesn't match expected")
+
+		_, first, _, _ := buffer.TryNextSeq()
+		for i := 21; i < 25; i++ {
+			buffer.TryNext()
 		}
-	})
 
-	t.Run("DroppedData", func(t *testing.T) {
-		size := 100
-		buffer := NewRing[int](size)
+		// Replay.
+		if err := buffer.Seek(first + 2); err != nil {
+			t.Fatal(err)
+		}
+		next, seq, ok, dropped := buffer.TryNextSeq()
+		if !ok || next != 22 || seq != first+2 || dropped != 0 {
+			t.Fatal("value read after Seek() doesn't match expected")
+		}
 
-		for i := 0; i < 10*size; i++ {
-			buffer.Push(i)
+		// Overwritten sequence.
+		if err := buffer.Seek(first - 1); err != ErrSequenceUnavailable {
+			t.Fatal("Seek() to overwritten sequence didn't fail")
+		}
+		// Future sequence.
+		if err := buffer.Seek(first + 11); err != ErrSequenceUnavailable {
+			t.Fatal("Seek() to future sequence didn't fail")
 		}
 
-		next, ok, dropped := buffer.TryNext()
-		if !ok {
-			t.Fatal("TryNext() returned true, expecting false")
+		// End of buffer.
+		if err := buffer.Seek(first + 10); err != nil {
+			t.Fatal(err)
 		}
-		if dropped != 900 {
-			t.Fatal("buffer reported some dropped value:", dropped)
+		_, ok, _ = buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true, expecting false")
 		}
-		if next != 900 {
+		buffer.Push(30)
+		next, _, _ = buffer.TryNext()
+		if next != 30 {
 			t.Fatal("value read from buffer doesn't match expected")
 		}
 	})
+
+	t.Run("Growth", func(t *testing.T) {
+		t.Run("Grow", func(t *testing.T) {
+			buffer := NewRing[int](4, WithRingGrowth[int](GrowthPolicy{MaxSize: 16}))
+
+			for i := 0; i < 6; i++ {
+				buffer.Push(i)
+			}
+
+			// First drop doubles size, unread value is kept.
+			next, ok, dropped := buffer.TryNext()
+			if !ok || next != 4 || dropped != 4 {
+				t.Fatal("value read from buffer doesn't match expected:", next, dropped)
+			}
+			if buffer.Size() != 8 {
+				t.Fatal("buffer didn't grow:", buffer.Size())
+			}
+			next, _, dropped = buffer.TryNext()
+			if next != 5 || dropped != 0 {
+				t.Fatal("value lost during resize")
+			}
+
+			for i := 0; i < 8; i++ {
+				buffer.Push(i)
+			}
+			for i := 0; i < 8; i++ {
+				next, ok, dropped := buffer.TryNext()
+				if !ok || next != i || dropped != 0 {
+					t.Fatal("value read from grown buffer doesn't match expected")
+				}
+			}
+
+			// Growth is bounded.
+			for j := 0; j < 10; j++ {
+				for i := 0; i < 100; i++ {
+					buffer.Push(i)
+				}
+				buffer.TryNext()
+			}
+			if buffer.Size() != 16 {
+				t.Fatal("buffer size doesn't match max size:", buffer.Size())
+			}
+		})
+
+		t.Run("Threshold", func(t *testing.T) {
+			buffer := NewRing[int](10, WithRingGrowth[int](GrowthPolicy{
+				MaxSize:       100,
+				GrowThreshold: 30,
+			}))
+
+			for j := 0; j < 2; j++ {
+				for i := 0; i < 20; i++ {
+					buffer.Push(i)
+				}
+				if _, _, dropped := buffer.TryNext(); dropped != 10 {
+					t.Fatal("buffer reported wrong number of dropped value:", dropped)
+				}
+				if buffer.Size() != 10 {
+					t.Fatal("buffer grew before threshold")
+				}
+				for _, ok, _ := buffer.TryNext(); ok; _, ok, _ = buffer.TryNext() {
+				}
+			}
+
+			for i := 0; i < 20; i++ {
+				buffer.Push(i)
+			}
+			buffer.TryNext()
+			if buffer.Size() != 20 {
+				t.Fatal("buffer didn't grow after threshold:", buffer.Size())
+			}
+		})
+
+		t.Run("Shrink", func(t *testing.T) {
+			buffer := NewRing[int](4, WithRingGrowth[int](GrowthPolicy{
+				MaxSize:     16,
+				ShrinkAfter: 4,
+			}))
+
+			for i := 0; i < 20; i++ {
+				buffer.Push(i)
+			}
+			for i := 0; i < 4; i++ {
+				buffer.TryNext()
+			}
+			if buffer.Size() != 8 {
+				t.Fatal("buffer didn't grow:", buffer.Size())
+			}
+
+			// Pending values doesn't fit in a smaller buffer.
+			for i := 0; i < 8; i++ {
+				buffer.Push(i)
+			}
+			buffer.TryNext()
+			if buffer.Size() != 8 {
+				t.Fatal("buffer shrunk while holding pending values")
+			}
+
+			for i := 1; i < 8; i++ {
+				next, ok, dropped := buffer.TryNext()
+				if !ok || next != i || dropped != 0 {
+					t.Fatal("value read from buffer doesn't match expected")
+				}
+			}
+			if buffer.Size() != 4 {
+				t.Fatal("buffer didn't shrink:", buffer.Size())
+			}
+
+			// Never below initial size.
+			for i := 0; i < 100; i++ {
+				buffer.Push(i)
+				buffer.TryNext()
+			}
+			if buffer.Size() != 4 {
+				t.Fatal("buffer shrunk below initial size")
+			}
+		})
+	})
 }
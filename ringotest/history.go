@@ -0,0 +1,317 @@
+package ringotest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/negrel/ringo"
+)
+
+// ErrNotLinearizable is returned by CheckHistory when a history doesn't match
+// a sequential lossy ring buffer.
+var ErrNotLinearizable = errors.New("ringotest: history isn't linearizable")
+
+// OpKind define kind of a recorded operation.
+type OpKind int
+
+const (
+	// OpPush is a Push operation.
+	OpPush OpKind = iota
+	// OpTryNext is a TryNext operation.
+	OpTryNext
+)
+
+// Operation define a recorded Push or TryNext operation. Call and Return are
+// logical timestamps: if an operation Return is lower than another's Call, it
+// happened before.
+type Operation struct {
+	Kind OpKind
+	// Client is the index of the Writer or -1 for Reader.
+	Client int
+	// Pushed or read value.
+	Value   int
+	Ok      bool
+	Dropped int
+	Call    uint64
+	Return  uint64
+}
+
+// Recorder records a concurrent history of operations on a buffer. Operations
+// are done through Writer and Reader handles, each handle keeps its own log so
+// recording doesn't serialize clients beyond a shared logical clock.
+type Recorder struct {
+	buffer ringo.Buffer[int]
+	clock  atomic.Uint64
+
+	mu      sync.Mutex
+	writers []*Writer
+	reader  *Reader
+}
+
+// NewRecorder returns a new Recorder of operations on the given buffer.
+func NewRecorder(buffer ringo.Buffer[int]) *Recorder {
+	return &Recorder{buffer: buffer}
+}
+
+// Writer returns a new writer handle. It must be used by a single go-routine.
+func (r *Recorder) Writer() *Writer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := &Writer{recorder: r, id: len(r.writers)}
+	r.writers = append(r.writers, w)
+
+	return w
+}
+
+// Reader returns the reader handle. It must be used by a single go-routine.
+func (r *Recorder) Reader() *Reader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reader == nil {
+		r.reader = &Reader{recorder: r}
+	}
+
+	return r.reader
+}
+
+// History returns recorded operations. It must not be called concurrently to
+// operations.
+func (r *Recorder) History() []Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := []Operation{}
+	for _, w := range r.writers {
+		history = append(history, w.ops...)
+	}
+	if r.reader != nil {
+		history = append(history, r.reader.ops...)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Call < history[j].Call
+	})
+
+	return history
+}
+
+// Writer define a writer handle of a Recorder.
+type Writer struct {
+	recorder *Recorder
+	id       int
+	ops      []Operation
+}
+
+// Push pushes a unique value to the buffer and records the operation.
+func (w *Writer) Push() {
+	call := w.recorder.clock.Add(1)
+	// Call timestamps are unique, use it as value.
+	w.recorder.buffer.Push(int(call))
+	ret := w.recorder.clock.Add(1)
+
+	w.ops = append(w.ops, Operation{
+		Kind:   OpPush,
+		Client: w.id,
+		Value:  int(call),
+		Ok:     true,
+		Call:   call,
+		Return: ret,
+	})
+}
+
+// Reader define the reader handle of a Recorder.
+type Reader struct {
+	recorder *Recorder
+	ops      []Operation
+}
+
+// TryNext reads a value from the buffer and records the operation.
+func (r *Reader) TryNext() (value int, ok bool, dropped int) {
+	call := r.recorder.clock.Add(1)
+	value, ok, dropped = r.recorder.buffer.TryNext()
+	ret := r.recorder.clock.Add(1)
+
+	r.ops = append(r.ops, Operation{
+		Kind:    OpTryNext,
+		Client:  -1,
+		Value:   value,
+		Ok:      ok,
+		Dropped: dropped,
+		Call:    call,
+		Return:  ret,
+	})
+
+	return
+}
+
+// CheckOption can be used to setup CheckHistory.
+type CheckOption func(*checkConfig)
+
+type checkConfig struct {
+	wastedSequences bool
+}
+
+// AllowWastedSequences makes CheckHistory accept buffers that may waste
+// sequence numbers (e.g. ManyToOne on collisions): reported drops may exceed
+// number of lost values.
+func AllowWastedSequences() CheckOption {
+	return func(c *checkConfig) {
+		c.wastedSequences = true
+	}
+}
+
+// CheckHistory checks a history recorded by a Recorder against a sequential
+// lossy ring buffer model: pushes are ordered by a sequence number and reader
+// reads increasing sequence numbers, each TryNext skipping exactly the
+// reported number of dropped values. Sequence of each read is derived from
+// dropped counts. CheckHistory reports values read twice or never pushed,
+// values read against real-time order of their pushes, values read before
+// being pushed or after an empty read that followed their push, and
+// inconsistent drop accounting.
+func CheckHistory(history []Operation, options ...CheckOption) error {
+	c := checkConfig{}
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	pushes := map[int]Operation{}
+	// Pushes of each writer, in order.
+	writers := map[int][]Operation{}
+	reads := []Operation{}
+	for _, op := range history {
+		switch op.Kind {
+		case OpPush:
+			pushes[op.Value] = op
+			writers[op.Client] = append(writers[op.Client], op)
+		case OpTryNext:
+			reads = append(reads, op)
+		}
+	}
+	sort.Slice(reads, func(i, j int) bool {
+		return reads[i].Call < reads[j].Call
+	})
+
+	read := map[int]bool{}
+	// Number of consumed sequence numbers (read or dropped).
+	consumed := 0
+	var prev *Operation
+	// Greatest Call of pushes read so far.
+	maxCall := uint64(0)
+	// Pushes known to be consumed by a quiescent empty read.
+	emptyAt := uint64(0)
+
+	for i, r := range reads {
+		consumed += r.Dropped
+		if r.Dropped < 0 {
+			return fmt.Errorf("%w: read %v reported %v dropped values", ErrNotLinearizable, i, r.Dropped)
+		}
+
+		if !r.Ok {
+			if isQuiescent(r.Call, writers) {
+				if err := checkQuiescentEmpty(c, r, consumed, writers); err != nil {
+					return err
+				}
+				emptyAt = r.Call
+			}
+			continue
+		}
+
+		push, ok := pushes[r.Value]
+		if !ok {
+			return fmt.Errorf("%w: read %v returned value %v that was never pushed", ErrNotLinearizable, i, r.Value)
+		}
+		if read[r.Value] {
+			return fmt.Errorf("%w: read %v returned value %v twice", ErrNotLinearizable, i, r.Value)
+		}
+		read[r.Value] = true
+
+		if push.Call > r.Return {
+			return fmt.Errorf("%w: read %v returned value %v before it was pushed", ErrNotLinearizable, i, r.Value)
+		}
+		if push.Return < maxCall {
+			return fmt.Errorf("%w: read %v returned value %v out of order", ErrNotLinearizable, i, r.Value)
+		}
+		if push.Return < emptyAt {
+			return fmt.Errorf("%w: read %v returned value %v after an empty read", ErrNotLinearizable, i, r.Value)
+		}
+		if push.Call > maxCall {
+			maxCall = push.Call
+		}
+
+		// Pushes that happened between previous read value and this one
+		// can't be read anymore: they must have been reported as dropped.
+		if prev != nil {
+			if lost := countBetween(writers, prev.Return, push.Call); r.Dropped < lost {
+				return fmt.Errorf("%w: read %v reported %v dropped values, at least %v values were lost", ErrNotLinearizable, i, r.Dropped, lost)
+			}
+		}
+		p := push
+		prev = &p
+
+		consumed++
+		if !c.wastedSequences {
+			if pushed := countCalledBefore(writers, r.Return); consumed > pushed {
+				return fmt.Errorf("%w: read %v consumed %v sequences, only %v values were pushed", ErrNotLinearizable, i, consumed, pushed)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isQuiescent returns true if no push is in progress at the given time.
+func isQuiescent(at uint64, writers map[int][]Operation) bool {
+	for _, ops := range writers {
+		// First push called after at.
+		j := sort.Search(len(ops), func(j int) bool { return ops[j].Call > at })
+		if j > 0 && ops[j-1].Return > at {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkQuiescentEmpty checks that every value pushed before an empty read with
+// no push in progress was consumed.
+func checkQuiescentEmpty(c checkConfig, r Operation, consumed int, writers map[int][]Operation) error {
+	pushed := countCalledBefore(writers, r.Call)
+	if consumed < pushed {
+		return fmt.Errorf("%w: empty read at %v while %v of %v pushed values were consumed", ErrNotLinearizable, r.Call, consumed, pushed)
+	}
+	if !c.wastedSequences && consumed > pushed {
+		return fmt.Errorf("%w: empty read at %v after consuming %v sequences, only %v values were pushed", ErrNotLinearizable, r.Call, consumed, pushed)
+	}
+
+	return nil
+}
+
+// countCalledBefore returns number of pushes called before the given time.
+func countCalledBefore(writers map[int][]Operation, at uint64) int {
+	count := 0
+	for _, ops := range writers {
+		count += sort.Search(len(ops), func(j int) bool { return ops[j].Call > at })
+	}
+
+	return count
+}
+
+// countBetween returns number of pushes called after from and returned
+// before to.
+func countBetween(writers map[int][]Operation, from, to uint64) int {
+	count := 0
+	for _, ops := range writers {
+		first := sort.Search(len(ops), func(j int) bool { return ops[j].Call > from })
+		end := sort.Search(len(ops), func(j int) bool { return ops[j].Return >= to })
+		if end > first {
+			count += end - first
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,151 @@
+package ringotest
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/negrel/ringo"
+)
+
+// push returns a push operation of a writer dedicated to it.
+func push(value int, call, ret uint64) Operation {
+	return Operation{Kind: OpPush, Client: value, Value: value, Ok: true, Call: call, Return: ret}
+}
+
+func tryNext(value int, ok bool, dropped int, call, ret uint64) Operation {
+	return Operation{Kind: OpTryNext, Client: -1, Value: value, Ok: ok, Dropped: dropped, Call: call, Return: ret}
+}
+
+// duplicateBuffer returns every value twice.
+type duplicateBuffer struct {
+	ringo.Buffer[int]
+	last  int
+	again bool
+}
+
+func (db *duplicateBuffer) TryNext() (int, bool, int) {
+	if db.again {
+		db.again = false
+		return db.last, true, 0
+	}
+
+	next, ok, dropped := db.Buffer.TryNext()
+	db.last, db.again = next, ok
+	return next, ok, dropped
+}
+
+func TestCheckHistory(t *testing.T) {
+	t.Run("SequentialRing", func(t *testing.T) {
+		recorder := NewRecorder(ringo.NewRing[int](10))
+		writer := recorder.Writer()
+		reader := recorder.Reader()
+
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			if rng.Intn(3) != 0 {
+				writer.Push()
+			} else {
+				reader.TryNext()
+			}
+		}
+		reader.TryNext()
+
+		if err := CheckHistory(recorder.History()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("ReadTwice", func(t *testing.T) {
+		recorder := NewRecorder(&duplicateBuffer{Buffer: ringo.NewRing[int](10)})
+		recorder.Writer().Push()
+		recorder.Reader().TryNext()
+		recorder.Reader().TryNext()
+
+		err := CheckHistory(recorder.History())
+		if !errors.Is(err, ErrNotLinearizable) {
+			t.Fatal("value read twice not detected")
+		}
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		tcases := []struct {
+			name    string
+			history []Operation
+		}{
+			{
+				name: "OutOfOrder",
+				history: []Operation{
+					push(1, 1, 2), push(3, 3, 4),
+					tryNext(3, true, 0, 5, 6), tryNext(1, true, 0, 7, 8),
+				},
+			},
+			{
+				name: "NeverPushed",
+				history: []Operation{
+					push(1, 1, 2), tryNext(42, true, 0, 3, 4),
+				},
+			},
+			{
+				name: "ReadBeforePush",
+				history: []Operation{
+					tryNext(3, true, 0, 1, 2), push(3, 3, 4),
+				},
+			},
+			{
+				name: "MissedRead",
+				history: []Operation{
+					push(1, 1, 2), tryNext(0, false, 0, 3, 4),
+				},
+			},
+			{
+				name: "ReadAfterEmpty",
+				history: []Operation{
+					push(1, 1, 2), tryNext(0, false, 1, 3, 4), tryNext(1, true, 0, 5, 6),
+				},
+			},
+			{
+				name: "UnreportedDrop",
+				history: []Operation{
+					push(1, 1, 2), push(3, 3, 4), push(5, 5, 6),
+					tryNext(1, true, 0, 7, 8), tryNext(5, true, 0, 9, 10),
+				},
+			},
+			{
+				name: "OverreportedDrop",
+				history: []Operation{
+					push(1, 1, 2), tryNext(1, true, 3, 3, 4),
+				},
+			},
+		}
+
+		for _, tcase := range tcases {
+			t.Run(tcase.name, func(t *testing.T) {
+				err := CheckHistory(tcase.history)
+				if !errors.Is(err, ErrNotLinearizable) {
+					t.Fatal("violation not detected")
+				}
+			})
+		}
+	})
+
+	t.Run("WastedSequences", func(t *testing.T) {
+		history := []Operation{
+			push(1, 1, 2), tryNext(1, true, 3, 3, 4), tryNext(0, false, 0, 5, 6),
+		}
+		if err := CheckHistory(history, AllowWastedSequences()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("ConcurrentPush", func(t *testing.T) {
+		// Push of 3 is in progress when 5 is read, reader can't read 3 anymore.
+		history := []Operation{
+			push(1, 1, 2), push(3, 3, 8), push(5, 4, 5),
+			tryNext(1, true, 0, 6, 7), tryNext(5, true, 1, 9, 10), tryNext(0, false, 0, 11, 12),
+		}
+		if err := CheckHistory(history); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+// Package ringotest provides a conformance test suite for ringo.Buffer
+// implementations.
+package ringotest
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/negrel/ringo"
+)
+
+// Factory returns a new empty buffer of the given size.
+type Factory func(size int) ringo.Buffer[int]
+
+// Option can be used to setup the buffer suite.
+type Option func(*config)
+
+type config struct {
+	size              int
+	concurrentWriters int
+	seed              int64
+	checkOptions      []CheckOption
+}
+
+// WithSize sets size of buffers created by the suite. Default is 100.
+func WithSize(size int) Option {
+	return func(c *config) {
+		c.size = size
+	}
+}
+
+// WithConcurrentWriters enables concurrent writers tests using the given
+// number of writers. Buffer must be safe for use by concurrent writers and a
+// single reader.
+func WithConcurrentWriters(writers int) Option {
+	return func(c *config) {
+		c.concurrentWriters = writers
+	}
+}
+
+// WithWastedSequences must be used for buffers that may waste sequence
+// numbers on collisions between writers (e.g. ManyToOne). Reported drops may
+// then exceed number of lost values.
+func WithWastedSequences() Option {
+	return func(c *config) {
+		c.checkOptions = append(c.checkOptions, AllowWastedSequences())
+	}
+}
+
+// WithSeed sets seed of random operations. Default is 1.
+func WithSeed(seed int64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// RunBufferSuite runs conformance tests against buffers returned by factory.
+// It checks size reporting, empty buffer behavior, FIFO order and dropped
+// accounting when writers lap the reader: a lossy buffer jumps to the oldest
+// value it still holds and reports every skipped value as dropped.
+//
+// Concurrent writers tests are run only if WithConcurrentWriters option is
+// provided, they are meant to be run with -race. They include a check of
+// recorded histories using CheckHistory.
+func RunBufferSuite(t *testing.T, factory Factory, options ...Option) {
+	c := config{size: 100, seed: 1}
+	for _, opt := range options {
+		opt(&c)
+	}
+
+	if c.size < 2 {
+		panic("buffer suite size must be greater than 1")
+	}
+
+	t.Run("Size", func(t *testing.T) {
+		buffer := factory(c.size)
+		if buffer.Size() != c.size {
+			t.Fatalf("Size() returned %v, expecting %v", buffer.Size(), c.size)
+		}
+	})
+
+	t.Run("ReadEmptyBuffer", func(t *testing.T) {
+		buffer := factory(c.size)
+		expectEmpty(t, buffer)
+	})
+
+	t.Run("SequentialReadWrite", func(t *testing.T) {
+		buffer := factory(c.size)
+		for i := 0; i < 10*c.size; i++ {
+			buffer.Push(i)
+			expectNext(t, buffer, i, 0)
+		}
+		expectEmpty(t, buffer)
+	})
+
+	t.Run("FullBufferThenEmptyIt", func(t *testing.T) {
+		buffer := factory(c.size)
+		for i := 0; i < c.size; i++ {
+			buffer.Push(i)
+		}
+		for i := 0; i < c.size; i++ {
+			expectNext(t, buffer, i, 0)
+		}
+		expectEmpty(t, buffer)
+	})
+
+	t.Run("DroppedData", func(t *testing.T) {
+		buffer := factory(c.size)
+		for i := 0; i < 10*c.size; i++ {
+			buffer.Push(i)
+		}
+
+		// Oldest held value is the first one written in current lap.
+		expectNext(t, buffer, 9*c.size, 9*c.size)
+		for i := 9*c.size + 1; i < 10*c.size; i++ {
+			expectNext(t, buffer, i, 0)
+		}
+		expectEmpty(t, buffer)
+	})
+
+	t.Run("RandomReadWrite", func(t *testing.T) {
+		buffer := factory(c.size)
+		rng := rand.New(rand.NewSource(c.seed))
+
+		pushed, last := 0, -1
+		for i := 0; i < 1000*c.size; i++ {
+			// Favor writes so reader is lapped from time to time.
+			if rng.Intn(3) != 0 {
+				buffer.Push(pushed)
+				pushed++
+				continue
+			}
+
+			next, ok, dropped := buffer.TryNext()
+			if !ok {
+				if last != pushed-1 || dropped != 0 {
+					t.Fatalf("TryNext() returned false after reading %v of %v values", last+1, pushed)
+				}
+				continue
+			}
+
+			// Every value between two reads is either read or dropped.
+			if next != last+1+dropped {
+				t.Fatalf("TryNext() returned %v with %v dropped value after %v", next, dropped, last)
+			}
+			last = next
+		}
+	})
+
+	if c.concurrentWriters > 0 {
+		t.Run("ConcurrentWriters", func(t *testing.T) {
+			runConcurrentWriters(t, factory(c.size), c.concurrentWriters, 10*c.size)
+		})
+
+		t.Run("Linearizable", func(t *testing.T) {
+			runLinearizable(t, factory(c.size), c.concurrentWriters, 10*c.size, c.checkOptions)
+		})
+	}
+}
+
+// runConcurrentWriters checks that values of each writer are read in order,
+// at most once and that no value is lost without being reported as dropped.
+func runConcurrentWriters(t *testing.T, buffer ringo.Buffer[int], writers, count int) {
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for w := 0; w < writers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				buffer.Push(w*count + i)
+				if i%64 == 0 {
+					runtime.Gosched()
+				}
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	last := make([]int, writers)
+	for i := range last {
+		last[i] = -1
+	}
+	total := 0
+	read := func() bool {
+		next, ok, dropped := buffer.TryNext()
+		total += dropped
+		if !ok {
+			return false
+		}
+		total++
+
+		w, i := next/count, next%count
+		if w < 0 || w >= writers {
+			t.Fatalf("TryNext() returned a value never pushed: %v", next)
+		}
+		if i <= last[w] {
+			t.Fatalf("TryNext() returned value %v of writer %v after value %v", i, w, last[w])
+		}
+		last[w] = i
+
+		return true
+	}
+
+loop:
+	for {
+		select {
+		case <-done:
+			break loop
+		default:
+			if !read() {
+				runtime.Gosched()
+			}
+		}
+	}
+	for read() {
+	}
+
+	// Collisions may waste sequence numbers that are reported as dropped.
+	if total < writers*count {
+		t.Fatalf("number of read and dropped value is less than expected, expected %v got %v", writers*count, total)
+	}
+}
+
+// runLinearizable records history of concurrent writers and a reader and
+// checks it.
+func runLinearizable(t *testing.T, buffer ringo.Buffer[int], writers, count int, options []CheckOption) {
+	recorder := NewRecorder(buffer)
+
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for w := 0; w < writers; w++ {
+		go func(writer *Writer) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				writer.Push()
+				if i%64 == 0 {
+					runtime.Gosched()
+				}
+			}
+		}(recorder.Writer())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	reader := recorder.Reader()
+loop:
+	for {
+		select {
+		case <-done:
+			break loop
+		default:
+			if _, ok, _ := reader.TryNext(); !ok {
+				runtime.Gosched()
+			}
+		}
+	}
+	// Drain buffer, last read is empty with no push in progress.
+	for _, ok, _ := reader.TryNext(); ok; _, ok, _ = reader.TryNext() {
+	}
+
+	if err := CheckHistory(recorder.History(), options...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectNext(t *testing.T, buffer ringo.Buffer[int], expected, expectedDropped int) {
+	t.Helper()
+
+	next, ok, dropped := buffer.TryNext()
+	if !ok {
+		t.Fatal("TryNext() returned false, expecting true")
+	}
+	if dropped != expectedDropped {
+		t.Fatalf("TryNext() reported %v dropped value, expecting %v", dropped, expectedDropped)
+	}
+	if next != expected {
+		t.Fatalf("TryNext() returned %v, expecting %v", next, expected)
+	}
+}
+
+func expectEmpty(t *testing.T, buffer ringo.Buffer[int]) {
+	t.Helper()
+
+	next, ok, dropped := buffer.TryNext()
+	if ok {
+		t.Fatal("TryNext() returned true, expecting false")
+	}
+	if dropped != 0 {
+		t.Fatal("TryNext() reported some dropped value:", dropped)
+	}
+	if next != 0 {
+		t.Fatal("TryNext() returned a non zero value:", next)
+	}
+}
@@ -0,0 +1,90 @@
+package ringo
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+var _ Buffer[any] = &Sampled[any]{}
+
+// Sampled define a Buffer decorator that only pushes a sample of values,
+// either 1 in N or with a fixed probability.
+type Sampled[T any] struct {
+	Buffer[T]
+	// Keep 1 in n values if n > 0.
+	n           uint64
+	probability float64
+	random      func() float64
+
+	pushed     atomic.Uint64
+	suppressed atomic.Uint64
+}
+
+// SampledOption can be used to setup the Sampled buffer.
+type SampledOption[T any] func(*Sampled[T])
+
+// WithSampledRandom sets source of random numbers in [0, 1) used for
+// probabilistic sampling. It must be safe for concurrent use if there are
+// concurrent writers. Default is math/rand.Float64.
+func WithSampledRandom[T any](random func() float64) SampledOption[T] {
+	return func(s *Sampled[T]) {
+		s.random = random
+	}
+}
+
+// NewSampled returns a new Sampled buffer wrapping the given one that pushes
+// 1 in n values: first one and every n-th after it.
+func NewSampled[T any](buffer Buffer[T], n int, options ...SampledOption[T]) *Sampled[T] {
+	if n <= 0 {
+		panic("sampling rate can't be negative or zero")
+	}
+
+	return newSampled(buffer, uint64(n), 0, options)
+}
+
+// NewSampledProbability returns a new Sampled buffer wrapping the given one
+// that pushes each value with the given probability.
+func NewSampledProbability[T any](buffer Buffer[T], probability float64, options ...SampledOption[T]) *Sampled[T] {
+	if probability <= 0 || probability > 1 {
+		panic("sampling probability must be in (0, 1]")
+	}
+
+	return newSampled(buffer, 0, probability, options)
+}
+
+func newSampled[T any](buffer Buffer[T], n uint64, probability float64, options []SampledOption[T]) *Sampled[T] {
+	s := &Sampled[T]{
+		Buffer:      buffer,
+		n:           n,
+		probability: probability,
+		random:      rand.Float64,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// Push implements Buffer. Data is suppressed if it isn't part of the sample.
+func (s *Sampled[T]) Push(data T) {
+	var keep bool
+	if s.n > 0 {
+		keep = (s.pushed.Add(1)-1)%s.n == 0
+	} else {
+		keep = s.random() < s.probability
+	}
+
+	if !keep {
+		s.suppressed.Add(1)
+		return
+	}
+
+	s.Buffer.Push(data)
+}
+
+// Suppressed returns total number of values left out of the sample.
+func (s *Sampled[T]) Suppressed() uint64 {
+	return s.suppressed.Load()
+}
@@ -0,0 +1,63 @@
+package ringo
+
+import "testing"
+
+func TestSampled(t *testing.T) {
+	t.Run("OneInN", func(t *testing.T) {
+		buffer := NewSampled[int](NewRing[int](100), 10)
+		for i := 0; i < 100; i++ {
+			buffer.Push(i)
+		}
+
+		for i := 0; i < 100; i += 10 {
+			next, ok, dropped := buffer.TryNext()
+			if !ok || next != i || dropped != 0 {
+				t.Fatal("value read from buffer doesn't match expected")
+			}
+		}
+		if _, ok, _ := buffer.TryNext(); ok {
+			t.Fatal("too many values were sampled")
+		}
+		if buffer.Suppressed() != 90 {
+			t.Fatal("Suppressed() returned", buffer.Suppressed())
+		}
+	})
+
+	t.Run("Probability", func(t *testing.T) {
+		random := []float64{0.1, 0.5, 0.24, 0.25, 0.9}
+		buffer := NewSampledProbability[int](NewRing[int](100), 0.25, WithSampledRandom[int](func() float64 {
+			r := random[0]
+			random = random[1:]
+			return r
+		}))
+		for i := 0; i < 5; i++ {
+			buffer.Push(i)
+		}
+
+		for _, expected := range []int{0, 2} {
+			next, ok, _ := buffer.TryNext()
+			if !ok || next != expected {
+				t.Fatal("value read from buffer doesn't match expected")
+			}
+		}
+		if _, ok, _ := buffer.TryNext(); ok {
+			t.Fatal("too many values were sampled")
+		}
+		if buffer.Suppressed() != 3 {
+			t.Fatal("Suppressed() returned", buffer.Suppressed())
+		}
+	})
+
+	t.Run("SeparateFromDropped", func(t *testing.T) {
+		buffer := NewSampled[int](NewRing[int](10), 2)
+		for i := 0; i < 100; i++ {
+			buffer.Push(i)
+		}
+
+		// 50 values were pushed to a ring of size 10.
+		_, _, dropped := buffer.TryNext()
+		if dropped != 40 || buffer.Suppressed() != 50 {
+			t.Fatalf("%v values dropped and %v suppressed, expecting 40 and 50", dropped, buffer.Suppressed())
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package ringo
+
+// schedPoint identifies a scheduling point: a step right before an atomic
+// operation where a controlled scheduler may pause a go-routine. Scheduling
+// points are no-op unless built with ringosched tag.
+type schedPoint int
+
+const (
+	schedPushClaim schedPoint = iota
+	schedPushLoad
+	schedPushSwap
+	schedTryNextLoad
+	schedTryNextAdvance
+	schedGrowthEnter
+	schedGrowthWait
+)
+
+func (sp schedPoint) String() string {
+	switch sp {
+	case schedPushClaim:
+		return "Push.Claim"
+	case schedPushLoad:
+		return "Push.Load"
+	case schedPushSwap:
+		return "Push.Swap"
+	case schedTryNextLoad:
+		return "TryNext.Load"
+	case schedTryNextAdvance:
+		return "TryNext.Advance"
+	case schedGrowthEnter:
+		return "Growth.Enter"
+	case schedGrowthWait:
+		return "Growth.Wait"
+	default:
+		return "unknown"
+	}
+}
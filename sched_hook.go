@@ -0,0 +1,14 @@
+//go:build ringosched
+
+package ringo
+
+// schedHook is called at every scheduling point when built with ringosched
+// tag. It is set by tests to control interleaving of go-routines.
+var schedHook func(point schedPoint)
+
+// schedYield calls schedHook if any.
+func schedYield(point schedPoint) {
+	if hook := schedHook; hook != nil {
+		hook(point)
+	}
+}
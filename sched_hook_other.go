@@ -0,0 +1,6 @@
+//go:build !ringosched
+
+package ringo
+
+// schedYield is a no-op scheduling point, see ringosched build tag.
+func schedYield(schedPoint) {}
@@ -0,0 +1,314 @@
+//go:build ringosched
+
+package ringo
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var schedSeed = flag.Int64("ringosched.seed", 0, "replay a single seed of randomized interleavings")
+
+var errSchedStepLimit = errors.New("scheduler step limit reached")
+
+// schedStrategy chooses next task to run among runnable ones.
+type schedStrategy interface {
+	choose(runnable int) int
+}
+
+type randomStrategy struct {
+	rng *rand.Rand
+}
+
+func (rs *randomStrategy) choose(runnable int) int {
+	return rs.rng.Intn(runnable)
+}
+
+// dfsStrategy follows a prefix of choices and picks first task afterward. It
+// records choices so interleavings can be enumerated.
+type dfsStrategy struct {
+	prefix []int
+	taken  []int
+	widths []int
+}
+
+func (ds *dfsStrategy) choose(runnable int) int {
+	choice := 0
+	if pos := len(ds.taken); pos < len(ds.prefix) {
+		choice = ds.prefix[pos]
+	}
+
+	ds.taken = append(ds.taken, choice)
+	ds.widths = append(ds.widths, runnable)
+
+	return choice
+}
+
+// next returns prefix of next interleaving to explore or false if every
+// interleaving was explored.
+func (ds *dfsStrategy) next() ([]int, bool) {
+	for i := len(ds.taken) - 1; i >= 0; i-- {
+		if ds.taken[i]+1 < ds.widths[i] {
+			prefix := append([]int{}, ds.taken[:i]...)
+			return append(prefix, ds.taken[i]+1), true
+		}
+	}
+
+	return nil, false
+}
+
+type schedTask struct {
+	id     int
+	fn     func()
+	resume chan struct{}
+	yield  chan struct{}
+	done   bool
+}
+
+// controlledScheduler runs tasks one at a time and switches between them at
+// every scheduling point of the package, so an interleaving is fully
+// determined by the choices of its strategy.
+type controlledScheduler struct {
+	strategy schedStrategy
+	maxSteps int
+	tasks    []*schedTask
+	current  *schedTask
+	trace    []string
+}
+
+func newControlledScheduler(strategy schedStrategy) *controlledScheduler {
+	return &controlledScheduler{strategy: strategy, maxSteps: 100000}
+}
+
+// Go registers a task, it is started by Run.
+func (cs *controlledScheduler) Go(fn func()) {
+	cs.tasks = append(cs.tasks, &schedTask{
+		id:     len(cs.tasks),
+		fn:     fn,
+		resume: make(chan struct{}),
+		yield:  make(chan struct{}),
+	})
+}
+
+// Run runs tasks until they're all done.
+func (cs *controlledScheduler) Run() error {
+	schedHook = cs.hook
+	defer func() { schedHook = nil }()
+
+	for _, task := range cs.tasks {
+		go func(task *schedTask) {
+			<-task.resume
+			defer func() {
+				task.done = true
+				task.yield <- struct{}{}
+			}()
+			task.fn()
+		}(task)
+	}
+
+	runnable := make([]*schedTask, 0, len(cs.tasks))
+	for step := 0; ; step++ {
+		runnable = runnable[:0]
+		for _, task := range cs.tasks {
+			if !task.done {
+				runnable = append(runnable, task)
+			}
+		}
+		if len(runnable) == 0 {
+			return nil
+		}
+		// Paused tasks are leaked.
+		if step == cs.maxSteps {
+			return errSchedStepLimit
+		}
+
+		cs.current = runnable[cs.strategy.choose(len(runnable))]
+		cs.current.resume <- struct{}{}
+		<-cs.current.yield
+	}
+}
+
+func (cs *controlledScheduler) hook(point schedPoint) {
+	task := cs.current
+	cs.trace = append(cs.trace, fmt.Sprintf("%v:%v", task.id, point))
+
+	task.yield <- struct{}{}
+	<-task.resume
+}
+
+// schedProgram registers tasks on scheduler and returns a function checking
+// results once they're done.
+type schedProgram func(cs *controlledScheduler) (check func() error)
+
+func runSchedProgram(program schedProgram, strategy schedStrategy) (*controlledScheduler, error) {
+	cs := newControlledScheduler(strategy)
+	check := program(cs)
+	if err := cs.Run(); err != nil {
+		return cs, err
+	}
+
+	return cs, check()
+}
+
+// runSeeds runs program under randomized interleavings, one per seed. Use
+// -ringosched.seed flag to replay a single seed.
+func runSeeds(t *testing.T, program schedProgram, seeds int) {
+	first, last := int64(1), int64(seeds)
+	if *schedSeed != 0 {
+		first, last = *schedSeed, *schedSeed
+	}
+
+	for seed := first; seed <= last; seed++ {
+		cs, err := runSchedProgram(program, &randomStrategy{rand.New(rand.NewSource(seed))})
+		if err != nil {
+			t.Fatalf("seed %v: %v\ntrace: %v\nreplay using: go test -tags ringosched -run '%v' -ringosched.seed %v",
+				seed, err, strings.Join(cs.trace, " "), t.Name(), seed)
+		}
+	}
+}
+
+// exploreInterleavings runs program under every interleaving, up to maxRuns.
+func exploreInterleavings(t *testing.T, program schedProgram, maxRuns int) int {
+	prefix := []int{}
+	for run := 1; ; run++ {
+		strategy := &dfsStrategy{prefix: prefix}
+		cs, err := runSchedProgram(program, strategy)
+		if err != nil {
+			t.Fatalf("interleaving %v: %v\ntrace: %v", strategy.taken, err, strings.Join(cs.trace, " "))
+		}
+
+		var ok bool
+		prefix, ok = strategy.next()
+		if !ok {
+			return run
+		}
+		if run == maxRuns {
+			t.Fatalf("more than %v interleavings", maxRuns)
+		}
+	}
+}
+
+// manyToOneProgram returns a program where writers push values concurrently
+// to a reader. It checks that values are read at most once, in order per
+// writer, and that every value is either read or reported as dropped.
+func manyToOneProgram(size, writers, pushes, reads int, options ...ManyToOneOption[int]) schedProgram {
+	return func(cs *controlledScheduler) func() error {
+		buffer := NewManyToOne[int](size, append([]ManyToOneOption[int]{
+			WithManyToOneCollisionHandler[int](CollisionHandlerFunc(func(any) {})),
+		}, options...)...)
+
+		for w := 0; w < writers; w++ {
+			w := w
+			cs.Go(func() {
+				for i := 0; i < pushes; i++ {
+					buffer.Push(w*pushes + i)
+				}
+			})
+		}
+
+		values := []int{}
+		total := 0
+		read := func() bool {
+			next, ok, dropped := buffer.TryNext()
+			total += dropped
+			if ok {
+				values = append(values, next)
+				total++
+			}
+			return ok
+		}
+
+		cs.Go(func() {
+			for i := 0; i < reads; i++ {
+				read()
+			}
+		})
+
+		return func() error {
+			// Every value must be either read or reported as dropped once
+			// writers are done.
+			for read() {
+			}
+
+			last := make([]int, writers)
+			for i := range last {
+				last[i] = -1
+			}
+			for _, v := range values {
+				w, i := v/pushes, v%pushes
+				if i <= last[w] {
+					return fmt.Errorf("value %v of writer %v read after value %v", i, w, last[w])
+				}
+				last[w] = i
+			}
+
+			if total < writers*pushes {
+				return fmt.Errorf("%v values read or dropped, expected at least %v", total, writers*pushes)
+			}
+
+			return nil
+		}
+	}
+}
+
+func TestControlledScheduler(t *testing.T) {
+	t.Run("Deterministic", func(t *testing.T) {
+		program := manyToOneProgram(2, 3, 3, 4)
+
+		traces := [][]string{}
+		for i := 0; i < 2; i++ {
+			cs, err := runSchedProgram(program, &randomStrategy{rand.New(rand.NewSource(42))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			traces = append(traces, cs.trace)
+		}
+
+		if !reflect.DeepEqual(traces[0], traces[1]) {
+			t.Fatal("same seed produced different interleavings")
+		}
+	})
+
+	t.Run("StepLimit", func(t *testing.T) {
+		cs := newControlledScheduler(&randomStrategy{rand.New(rand.NewSource(1))})
+		cs.maxSteps = 10
+		cs.Go(func() {
+			for {
+				schedYield(schedPushClaim)
+			}
+		})
+
+		if err := cs.Run(); err != errSchedStepLimit {
+			t.Fatal("Run() didn't stop after step limit")
+		}
+	})
+}
+
+func TestManyToOneInterleavings(t *testing.T) {
+	t.Run("Exhaustive", func(t *testing.T) {
+		runs := exploreInterleavings(t, manyToOneProgram(1, 2, 1, 1), 100000)
+		if runs < 2 {
+			t.Fatal("a single interleaving explored")
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		runSeeds(t, manyToOneProgram(2, 3, 3, 4), 1000)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		runSeeds(t, manyToOneProgram(3, 2, 4, 6, WithManyToOneReplay[int]()), 1000)
+	})
+
+	t.Run("Growth", func(t *testing.T) {
+		runSeeds(t, manyToOneProgram(1, 2, 4, 6, WithManyToOneGrowth[int](GrowthPolicy{
+			MaxSize:     8,
+			ShrinkAfter: 2,
+		})), 1000)
+	})
+}
@@ -0,0 +1,116 @@
+package ringo
+
+import (
+	"math/rand"
+	"runtime"
+)
+
+var _ Buffer[any] = &Sharded[any]{}
+
+// Sharded define a ring buffer made of multiple ManyToOne shards to reduce
+// contention between concurrent writers. Each Push writes to a random shard
+// and reader drains shards in a round robin fashion. Values are read in FIFO
+// order within a shard but not globally: values of a single writer using Push
+// are reordered. Use PushShard with a per writer key to keep their order.
+//
+// Sharded is safe for use by concurrent writers and a single reader.
+type Sharded[T any] struct {
+	shards []*ManyToOne[T]
+	// Next shard to read, only used by reader.
+	next int
+}
+
+// ShardedOption can be used to setup the Sharded ring buffer.
+type ShardedOption[T any] func(*shardedConfig[T])
+
+type shardedConfig[T any] struct {
+	shards           int
+	manyToOneOptions []ManyToOneOption[T]
+}
+
+// WithShardCount sets number of shards. Default is runtime.GOMAXPROCS(0).
+func WithShardCount[T any](shards int) ShardedOption[T] {
+	return func(c *shardedConfig[T]) {
+		c.shards = shards
+	}
+}
+
+// WithShardOptions sets options of ManyToOne shards.
+func WithShardOptions[T any](options ...ManyToOneOption[T]) ShardedOption[T] {
+	return func(c *shardedConfig[T]) {
+		c.manyToOneOptions = append(c.manyToOneOptions, options...)
+	}
+}
+
+// NewSharded returns a new Sharded ring buffer with the given total size. Size
+// is split as evenly as possible between shards.
+func NewSharded[T any](size int, options ...ShardedOption[T]) *Sharded[T] {
+	if size <= 0 {
+		panic("ring buffer size can't be negative or zero")
+	}
+
+	config := shardedConfig[T]{
+		shards: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.shards <= 0 {
+		panic("number of shards can't be negative or zero")
+	}
+	if config.shards > size {
+		config.shards = size
+	}
+
+	shards := make([]*ManyToOne[T], config.shards)
+	for i := range shards {
+		shardSize := size / config.shards
+		if i < size%config.shards {
+			shardSize++
+		}
+		shards[i] = NewManyToOne[T](shardSize, config.manyToOneOptions...)
+	}
+
+	return &Sharded[T]{shards: shards}
+}
+
+// Size implements Buffer.
+func (s *Sharded[T]) Size() int {
+	size := 0
+	for _, shard := range s.shards {
+		size += shard.Size()
+	}
+
+	return size
+}
+
+// Push implements Buffer. Data is written to a random shard, shards aren't
+// bound to a P as Go doesn't expose them.
+func (s *Sharded[T]) Push(data T) {
+	// Top-level functions of math/rand don't lock unless rand.Seed was
+	// called.
+	s.shards[rand.Intn(len(s.shards))].Push(data)
+}
+
+// PushShard writes data to the shard selected by key. Values pushed with the
+// same key are read in FIFO order.
+func (s *Sharded[T]) PushShard(key uint64, data T) {
+	s.shards[key%uint64(len(s.shards))].Push(data)
+}
+
+// TryNext implements Buffer. Dropped is the number of values dropped by the
+// shard the value is read from.
+func (s *Sharded[T]) TryNext() (result T, ok bool, dropped int) {
+	for i := 0; i < len(s.shards); i++ {
+		shard := s.shards[s.next]
+		s.next = (s.next + 1) % len(s.shards)
+
+		result, ok, dropped = shard.TryNext()
+		if ok {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,151 @@
+package ringo
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSharded(t *testing.T) {
+	t.Run("Size", func(t *testing.T) {
+		buffer := NewSharded[int](1000, WithShardCount[int](4))
+		if buffer.Size() != 1000 {
+			t.Fatal("size doesn't match expected:", buffer.Size())
+		}
+
+		buffer = NewSharded[int](10, WithShardCount[int](3))
+		if buffer.Size() != 10 {
+			t.Fatal("size doesn't match expected:", buffer.Size())
+		}
+
+		buffer = NewSharded[int](2, WithShardCount[int](8))
+		if len(buffer.shards) != 2 {
+			t.Fatal("number of shards isn't bounded by size:", len(buffer.shards))
+		}
+	})
+
+	t.Run("SingleShard", func(t *testing.T) {
+		buffer := NewSharded[int](100, WithShardCount[int](1))
+
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i)
+		}
+
+		next, ok, dropped := buffer.TryNext()
+		if !ok {
+			t.Fatal("TryNext() returned false, expecting true")
+		}
+		if dropped != 900 || next != 900 {
+			t.Fatal("buffer reported wrong number of dropped value:", dropped)
+		}
+	})
+
+	t.Run("PushShard", func(t *testing.T) {
+		writerCount := 8
+		count := 1000
+		buffer := NewSharded[[2]int](writerCount*count*2, WithShardCount[[2]int](4))
+
+		var wg sync.WaitGroup
+		wg.Add(writerCount)
+		for i := 0; i < writerCount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < count; j++ {
+					buffer.PushShard(uint64(i), [2]int{i, j})
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		last := make([]int, writerCount)
+		for i := range last {
+			last[i] = -1
+		}
+		for {
+			next, ok, _ := buffer.TryNext()
+			if !ok {
+				break
+			}
+			if next[1] != last[next[0]]+1 {
+				t.Fatal("values of a writer were reordered:", next)
+			}
+			last[next[0]] = next[1]
+		}
+	})
+
+	t.Run("ReadEmptyBuffer", func(t *testing.T) {
+		buffer := NewSharded[int](100, WithShardCount[int](4))
+
+		_, ok, dropped := buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned true, expecting false")
+		}
+		if dropped != 0 {
+			t.Fatal("buffer reported some dropped value:", dropped)
+		}
+	})
+
+	t.Run("MultipleWriter", func(t *testing.T) {
+		writerCount := 8
+		count := 1000
+		buffer := NewSharded[int](writerCount*count*2, WithShardCount[int](4))
+
+		var wg sync.WaitGroup
+		wg.Add(writerCount)
+		for i := 0; i < writerCount; i++ {
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < count; j++ {
+					buffer.Push(i*count + j)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		read := make([]bool, writerCount*count)
+		for {
+			next, ok, dropped := buffer.TryNext()
+			if !ok {
+				break
+			}
+			if dropped != 0 {
+				t.Fatal("buffer reported some dropped value:", dropped)
+			}
+			if read[next] {
+				t.Fatal("value read twice:", next)
+			}
+			read[next] = true
+		}
+
+		for i, ok := range read {
+			if !ok {
+				t.Fatal("value never read:", i)
+			}
+		}
+	})
+
+	t.Run("PerShardFIFO", func(t *testing.T) {
+		buffer := NewSharded[int](400, WithShardCount[int](4))
+
+		for i := 0; i < 200; i++ {
+			buffer.Push(i)
+		}
+
+		// Values of a shard are read every 4 reads as shards are drained in
+		// a round robin fashion.
+		last := make([]int, 4)
+		for i := range last {
+			last[i] = -1
+		}
+		for i := 0; i < 200; i++ {
+			next, ok, _ := buffer.TryNext()
+			if !ok {
+				break
+			}
+			shard := (buffer.next + 3) % 4
+			if next <= last[shard] {
+				t.Fatal("shard values read out of order")
+			}
+			last[shard] = next
+		}
+	})
+}
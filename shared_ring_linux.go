@@ -0,0 +1,332 @@
+package ringo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// Shared memory layout:
+//
+//	header | write index | read index | slot 0 | slot 1 | ...
+//
+// Write and read indexes are on their own cache line. A slot contains a state
+// word, the length of the encoded value and the encoded value. State is 0 if
+// slot is empty, sequence number + 1 otherwise. sharedRingBusy bit is set
+// while a writer is writing the slot.
+const (
+	sharedRingMagic          = "RINGOSHM"
+	sharedRingVersion        = 1
+	sharedRingWriteIndex     = 64
+	sharedRingReadIndex      = 128
+	sharedRingSlotsOffset    = 192
+	sharedRingSlotHeaderSize = 16
+	sharedRingBusy           = 1 << 63
+	// Attempts to claim a slot locked by an older writer before giving up.
+	sharedRingClaimAttempts = 1000
+)
+
+var _ Buffer[any] = &SharedRing[any]{}
+
+// SharedRing define a ring buffer stored in a memory mapped file (e.g. under
+// /dev/shm) and shared between processes. It is safe for use by concurrent
+// writers and a single reader, in one or multiple processes. Like ManyToOne,
+// writers overwrite oldest values when buffer is full.
+//
+// A writer process that dies while writing a slot leaves it locked, the reader
+// won't read past it until writers lap it and writers reaching it are delayed.
+type SharedRing[T any] struct {
+	mem              []byte
+	codec            Codec[T]
+	slotSize         uint64
+	slotStride       uint64
+	capacity         uint64
+	collisionHandler CollisionHandler
+
+	err atomic.Pointer[error]
+	// Values dropped since last read.
+	dropped int
+}
+
+// SharedRingOption can be used to setup the SharedRing.
+type SharedRingOption[T any] func(*SharedRing[T])
+
+// WithSharedRingCollisionHandler sets SharedRing ring buffer collision handler.
+// If this option is not provided ring buffer defaults to global handler.
+func WithSharedRingCollisionHandler[T any](ch CollisionHandler) SharedRingOption[T] {
+	return func(sr *SharedRing[T]) {
+		sr.collisionHandler = ch
+	}
+}
+
+// CreateSharedRing creates a new SharedRing file at the given path with the
+// given number of slots of slotSize bytes and attaches to it. It fails if file
+// already exists.
+func CreateSharedRing[T any](path string, size, slotSize int, codec Codec[T], options ...SharedRingOption[T]) (*SharedRing[T], error) {
+	if size <= 0 {
+		panic("ring buffer size can't be negative or zero")
+	}
+	if slotSize <= 0 {
+		panic("ring buffer slot size can't be negative or zero")
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stride := sharedRingSlotStride(uint64(slotSize))
+	length := int64(sharedRingSlotsOffset + uint64(size)*stride)
+	err = f.Truncate(length)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	mem, err := syscall.Mmap(int(f.Fd()), 0, int(length), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	*sharedRingUint32(mem, 8) = sharedRingVersion
+	*sharedRingUint32(mem, 12) = uint32(slotSize)
+	*sharedRingUint64(mem, 16) = uint64(size)
+	// First increment will overflow to 0.
+	atomic.StoreUint64(sharedRingUint64(mem, sharedRingWriteIndex), ^uint64(0))
+	// Magic is written last so attaching processes never see a partial header.
+	copy(mem[:8], sharedRingMagic)
+
+	return newSharedRing(mem, codec, options...), nil
+}
+
+// OpenSharedRing attaches to an existing SharedRing file created with
+// CreateSharedRing. Size and slot size are read from the file header.
+func OpenSharedRing[T any](path string, codec Codec[T], options ...SharedRingOption[T]) (*SharedRing[T], error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() < sharedRingSlotsOffset {
+		return nil, fmt.Errorf("%w: file too small", ErrInvalidFileRing)
+	}
+
+	mem, err := syscall.Mmap(int(f.Fd()), 0, int(stat.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(mem[:8]) != sharedRingMagic || *sharedRingUint32(mem, 8) != sharedRingVersion {
+		_ = syscall.Munmap(mem)
+		return nil, fmt.Errorf("%w: bad magic or version", ErrInvalidFileRing)
+	}
+
+	slotSize := uint64(*sharedRingUint32(mem, 12))
+	size := *sharedRingUint64(mem, 16)
+	if size == 0 || uint64(stat.Size()) != sharedRingSlotsOffset+size*sharedRingSlotStride(slotSize) {
+		_ = syscall.Munmap(mem)
+		return nil, fmt.Errorf("%w: file size doesn't match header", ErrInvalidFileRing)
+	}
+
+	return newSharedRing(mem, codec, options...), nil
+}
+
+func newSharedRing[T any](mem []byte, codec Codec[T], options ...SharedRingOption[T]) *SharedRing[T] {
+	slotSize := uint64(*sharedRingUint32(mem, 12))
+
+	sr := &SharedRing[T]{
+		mem:              mem,
+		codec:            codec,
+		slotSize:         slotSize,
+		slotStride:       sharedRingSlotStride(slotSize),
+		capacity:         *sharedRingUint64(mem, 16),
+		collisionHandler: *globalCollisionHandler.Load(),
+	}
+
+	for _, opt := range options {
+		opt(sr)
+	}
+
+	return sr
+}
+
+// sharedRingSlotStride returns size of slots, keeping state words 8 bytes
+// aligned.
+func sharedRingSlotStride(slotSize uint64) uint64 {
+	return sharedRingSlotHeaderSize + (slotSize+7)&^7
+}
+
+func sharedRingUint64(mem []byte, offset uint64) *uint64 {
+	return (*uint64)(unsafe.Pointer(&mem[offset]))
+}
+
+func sharedRingUint32(mem []byte, offset uint64) *uint32 {
+	return (*uint32)(unsafe.Pointer(&mem[offset]))
+}
+
+func (sr *SharedRing[T]) setErr(err error) {
+	sr.err.CompareAndSwap(nil, &err)
+}
+
+// Err returns the first error encountered by Push or TryNext in this process.
+func (sr *SharedRing[T]) Err() error {
+	if err := sr.err.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
+// Size implements Buffer.
+func (sr *SharedRing[T]) Size() int {
+	return int(sr.capacity)
+}
+
+// Push implements Buffer.
+func (sr *SharedRing[T]) Push(data T) {
+	encoded, err := sr.codec.Encode(nil, data)
+	if err != nil {
+		sr.setErr(err)
+		return
+	}
+	if uint64(len(encoded)) > sr.slotSize {
+		sr.setErr(ErrValueTooLarge)
+		return
+	}
+
+	writeIndexPtr := sharedRingUint64(sr.mem, sharedRingWriteIndex)
+
+	for {
+		writeIndex := atomic.AddUint64(writeIndexPtr, 1)
+		slot := sharedRingSlotsOffset + (writeIndex%sr.capacity)*sr.slotStride
+		state := sharedRingUint64(sr.mem, slot)
+
+		if sr.claim(state, writeIndex) {
+			atomic.StoreUint32(sharedRingUint32(sr.mem, slot+8), uint32(len(encoded)))
+			copy(sr.mem[slot+sharedRingSlotHeaderSize:], encoded)
+			atomic.StoreUint64(state, writeIndex+1)
+
+			return
+		}
+
+		sr.collisionHandler.OnCollision(sr)
+	}
+}
+
+// claim locks slot state for writing the given sequence number. It returns
+// false if slot holds a newer value: writer was lapped and must claim a new
+// sequence number. Otherwise, claim is retried with the same sequence number
+// as giving it up would leave a hole where reader stalls. A slot locked by an
+// older writer is waited for a bounded number of attempts, in case its process
+// died while writing it.
+func (sr *SharedRing[T]) claim(state *uint64, writeIndex uint64) bool {
+	for attempt := 0; attempt < sharedRingClaimAttempts; attempt++ {
+		old := atomic.LoadUint64(state)
+		if seq := old &^ sharedRingBusy; seq != 0 && seq-1 > writeIndex {
+			return false
+		}
+
+		if old&sharedRingBusy == 0 && atomic.CompareAndSwapUint64(state, old, (writeIndex+1)|sharedRingBusy) {
+			return true
+		}
+
+		sr.collisionHandler.OnCollision(sr)
+		runtime.Gosched()
+	}
+
+	return false
+}
+
+// TryNext implements Buffer.
+func (sr *SharedRing[T]) TryNext() (result T, ok bool, dropped int) {
+	readIndexPtr := sharedRingUint64(sr.mem, sharedRingReadIndex)
+
+	for {
+		readIndex := atomic.LoadUint64(readIndexPtr)
+		seq, data, valid, found := sr.readSlot(readIndex)
+		if !found {
+			return
+		}
+
+		// cell have been overwritten
+		if seq > readIndex {
+			sr.dropped += int(seq - readIndex)
+		}
+		atomic.StoreUint64(readIndexPtr, seq+1)
+
+		// Skip corrupted or undecodable values, newer ones may be readable.
+		if !valid {
+			sr.dropped++
+			continue
+		}
+		value, err := sr.codec.Decode(data)
+		if err != nil {
+			sr.setErr(err)
+			sr.dropped++
+			continue
+		}
+
+		dropped = sr.dropped
+		sr.dropped = 0
+
+		return value, true, dropped
+	}
+}
+
+// readSlot copies slot expected to hold the given sequence number. found is
+// false if slot is empty, being written or was already read. valid is false
+// if slot header is corrupted.
+func (sr *SharedRing[T]) readSlot(readIndex uint64) (seq uint64, data []byte, valid, found bool) {
+	slot := sharedRingSlotsOffset + (readIndex%sr.capacity)*sr.slotStride
+	state := sharedRingUint64(sr.mem, slot)
+
+	for {
+		s := atomic.LoadUint64(state)
+		// empty or being written.
+		if s == 0 || s&sharedRingBusy != 0 {
+			return
+		}
+
+		// already read
+		seq = s - 1
+		if seq < readIndex {
+			return
+		}
+
+		length := uint64(atomic.LoadUint32(sharedRingUint32(sr.mem, slot+8)))
+		if length <= sr.slotSize {
+			data = append(data[:0], sr.mem[slot+sharedRingSlotHeaderSize:slot+sharedRingSlotHeaderSize+length]...)
+		}
+
+		// cell have been overwritten while reading it.
+		if atomic.LoadUint64(state) != s {
+			continue
+		}
+
+		// Writers never store a length greater than slot size.
+		return seq, data, length <= sr.slotSize, true
+	}
+}
+
+// Close detaches from shared memory. Underlying file isn't removed.
+func (sr *SharedRing[T]) Close() error {
+	if sr.mem == nil {
+		return errors.New("ringo: shared ring already closed")
+	}
+
+	err := syscall.Munmap(sr.mem)
+	sr.mem = nil
+
+	return err
+}
@@ -0,0 +1,263 @@
+package ringo
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+const (
+	sharedRingHelperPath   = "RINGO_SHARED_RING_PATH"
+	sharedRingHelperWriter = "RINGO_SHARED_RING_WRITER"
+	sharedRingHelperCount  = "RINGO_SHARED_RING_COUNT"
+)
+
+// TestSharedRingHelperProcess isn't a real test, it is a writer process
+// started by TestSharedRing.
+func TestSharedRingHelperProcess(t *testing.T) {
+	path := os.Getenv(sharedRingHelperPath)
+	if path == "" {
+		t.Skip("helper process")
+	}
+
+	writer, _ := strconv.ParseInt(os.Getenv(sharedRingHelperWriter), 10, 64)
+	count, _ := strconv.ParseInt(os.Getenv(sharedRingHelperCount), 10, 64)
+
+	sr, err := OpenSharedRing[int64](path, BinaryCodec[int64]{},
+		WithSharedRingCollisionHandler[int64](CollisionHandlerFunc(func(_ any) {})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sr.Close()
+
+	for i := int64(0); i < count; i++ {
+		sr.Push(writer<<32 | i)
+	}
+
+	if sr.Err() != nil {
+		t.Fatal(sr.Err())
+	}
+}
+
+func startSharedRingWriters(t *testing.T, path string, writerCount int, count int) []*exec.Cmd {
+	t.Helper()
+
+	cmds := make([]*exec.Cmd, writerCount)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestSharedRingHelperProcess$")
+		cmd.Env = append(os.Environ(),
+			sharedRingHelperPath+"="+path,
+			sharedRingHelperWriter+"="+strconv.Itoa(i),
+			sharedRingHelperCount+"="+strconv.Itoa(count),
+		)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds[i] = cmd
+	}
+
+	return cmds
+}
+
+func TestSharedRing(t *testing.T) {
+	t.Run("SequentialReadWrite", func(t *testing.T) {
+		sr, err := CreateSharedRing[int64](filepath.Join(t.TempDir(), "ring"), 100, 8, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sr.Close()
+
+		for i := int64(0); i < 1000; i++ {
+			sr.Push(i)
+			expectNext(t, sr, i, 0)
+		}
+		expectEmpty(t, sr)
+	})
+
+	t.Run("DroppedData", func(t *testing.T) {
+		sr, err := CreateSharedRing[int64](filepath.Join(t.TempDir(), "ring"), 100, 8, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sr.Close()
+
+		for i := int64(0); i < 1000; i++ {
+			sr.Push(i)
+		}
+
+		expectNext(t, sr, 900, 900)
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		reader, err := CreateSharedRing[int64](path, 100, 8, JSONCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		writer, err := OpenSharedRing[string](path, StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer writer.Close()
+
+		writer.Push("1")
+		writer.Push("x")
+		writer.Push("3")
+
+		expectNext(t, reader, 1, 0)
+		// Value that couldn't be decoded is skipped and reported.
+		expectNext(t, reader, 3, 1)
+		if reader.Err() == nil {
+			t.Fatal("decode error wasn't reported")
+		}
+		expectEmpty(t, reader)
+	})
+
+	t.Run("CorruptedSlot", func(t *testing.T) {
+		sr, err := CreateSharedRing[int64](filepath.Join(t.TempDir(), "ring"), 100, 8, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sr.Close()
+
+		for i := int64(0); i < 3; i++ {
+			sr.Push(i)
+		}
+		expectNext(t, sr, 0, 0)
+
+		// Corrupt length of next slot.
+		readIndex := *sharedRingUint64(sr.mem, sharedRingReadIndex)
+		slot := sharedRingSlotsOffset + (readIndex%sr.capacity)*sr.slotStride
+		*sharedRingUint32(sr.mem, slot+8) = 1 << 31
+
+		expectNext(t, sr, 2, 1)
+		expectEmpty(t, sr)
+	})
+
+	t.Run("AttachByPath", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		reader, err := CreateSharedRing[int64](path, 100, 8, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		writer, err := OpenSharedRing[int64](path, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer writer.Close()
+
+		if writer.Size() != 100 {
+			t.Fatal("size read from header doesn't match expected:", writer.Size())
+		}
+
+		writer.Push(42)
+		expectNext(t, reader, 42, 0)
+	})
+
+	t.Run("CreateExisting", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		sr, err := CreateSharedRing[int64](path, 100, 8, BinaryCodec[int64]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sr.Close()
+
+		_, err = CreateSharedRing[int64](path, 100, 8, BinaryCodec[int64]{})
+		if !errors.Is(err, os.ErrExist) {
+			t.Fatal("CreateSharedRing() on existing file didn't fail:", err)
+		}
+	})
+
+	t.Run("OpenInvalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ring")
+		err := os.WriteFile(path, make([]byte, 4096), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = OpenSharedRing[int64](path, BinaryCodec[int64]{})
+		if !errors.Is(err, ErrInvalidFileRing) {
+			t.Fatal("OpenSharedRing() didn't return ErrInvalidFileRing:", err)
+		}
+	})
+
+	t.Run("MultipleWriterProcess", func(t *testing.T) {
+		for _, size := range []int{100000, 64} {
+			t.Run(strconv.Itoa(size), func(t *testing.T) {
+				writerCount := 4
+				count := 10000
+
+				path := filepath.Join(t.TempDir(), "ring")
+				sr, err := CreateSharedRing[int64](path, size, 8, BinaryCodec[int64]{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer sr.Close()
+
+				cmds := startSharedRingWriters(t, path, writerCount, count)
+				exited := make(chan error, writerCount)
+				for _, cmd := range cmds {
+					go func(cmd *exec.Cmd) {
+						exited <- cmd.Wait()
+					}(cmd)
+				}
+
+				lastRead := make([]int64, writerCount)
+				for i := range lastRead {
+					lastRead[i] = -1
+				}
+				totalRead := 0
+				totalDropped := 0
+
+				read := func() bool {
+					v, ok, dropped := sr.TryNext()
+					totalDropped += dropped
+					if !ok {
+						return false
+					}
+					totalRead++
+
+					writer, i := v>>32, v&(1<<32-1)
+					if writer < 0 || writer >= int64(writerCount) || i >= int64(count) {
+						t.Fatal("value read from buffer doesn't match expected:", v)
+					}
+					if i <= lastRead[writer] {
+						t.Fatalf("value of writer %v read out of order: %v after %v", writer, i, lastRead[writer])
+					}
+					lastRead[writer] = i
+					return true
+				}
+
+				for running := writerCount; running > 0; {
+					select {
+					case err := <-exited:
+						if err != nil {
+							t.Fatal("writer process failed:", err)
+						}
+						running--
+					default:
+						read()
+					}
+				}
+				for read() {
+				}
+
+				if totalDropped+totalRead < writerCount*count {
+					t.Fatalf("number of read and dropped value is less than expected, expected %v got %v", writerCount*count, totalDropped+totalRead)
+				}
+				if size > writerCount*count && totalRead != writerCount*count {
+					t.Fatalf("number of read value doesn't match expected, expected %v got %v", writerCount*count, totalRead)
+				}
+			})
+		}
+	})
+}
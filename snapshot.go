@@ -0,0 +1,192 @@
+package ringo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Snapshot layout:
+//
+//	magic | size | write index | read index | box count | box 0 | box 1 | ...
+//
+// A box is its index followed by the length of its encoded value and the
+// encoded value.
+const snapshotMagic = "RINGOSN1"
+
+// snapshotMaxSize bounds buffer allocated when loading a corrupted snapshot.
+const snapshotMaxSize = 1 << 24
+
+// ErrInvalidSnapshot is returned when loading a malformed snapshot.
+var ErrInvalidSnapshot = errors.New("ringo: invalid snapshot")
+
+// Save writes unread values and sequence counters of the ring buffer to w.
+// Values are serialized using codec.
+func (r *Ring[T]) Save(w io.Writer, codec Codec[T]) error {
+	boxes := []box[T]{}
+	for _, b := range r.buffer {
+		if !seqBefore(b.index, r.readIndex) {
+			boxes = append(boxes, b)
+		}
+	}
+
+	return writeSnapshot(w, codec, r.Size(), r.writeIndex, r.readIndex, boxes)
+}
+
+// LoadRing returns a new Ring restored from a snapshot written by Ring.Save.
+// Values are deserialized using codec. Snapshots of buffers larger than 1<<24
+// values are rejected.
+func LoadRing[T any](r io.Reader, codec Codec[T], options ...RingOption[T]) (*Ring[T], error) {
+	size, writeIndex, readIndex, boxes, err := readSnapshot(r, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	ring := NewRing[T](size, options...)
+	ring.writeIndex = writeIndex
+	ring.readIndex = readIndex
+	for _, b := range boxes {
+		ring.buffer[b.index%uint64(size)] = b
+		// Sequence 0 is only written once write index wrapped.
+		if b.index == 0 {
+			ring.wrapped = true
+		}
+	}
+
+	return ring, nil
+}
+
+// Save writes unread values and sequence counters of the ring buffer to w.
+// Values are serialized using codec. Buffer must be quiesced: Save must not be
+// called concurrently to Push or TryNext.
+func (mto *ManyToOne[T]) Save(w io.Writer, codec Codec[T]) error {
+	readIndex := mto.readIndex.Load()
+
+	boxes := []box[T]{}
+	for i := range mto.buffer {
+		b := mto.buffer[i].Load()
+		if b != nil && !seqBefore(b.index, readIndex) {
+			boxes = append(boxes, *b)
+		}
+	}
+
+	return writeSnapshot(w, codec, mto.Size(), mto.writeIndex.Load(), readIndex, boxes)
+}
+
+// LoadManyToOne returns a new ManyToOne restored from a snapshot written by
+// ManyToOne.Save. Values are deserialized using codec. Snapshots of buffers
+// larger than 1<<24 values are rejected.
+func LoadManyToOne[T any](r io.Reader, codec Codec[T], options ...ManyToOneOption[T]) (*ManyToOne[T], error) {
+	size, writeIndex, readIndex, boxes, err := readSnapshot(r, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	mto := NewManyToOne[T](size, options...)
+	mto.writeIndex.Store(writeIndex)
+	mto.readIndex.Store(readIndex)
+	for i := range boxes {
+		mto.buffer[boxes[i].index%uint64(size)].Store(&boxes[i])
+	}
+
+	return mto, nil
+}
+
+func writeSnapshot[T any](w io.Writer, codec Codec[T], size int, writeIndex, readIndex uint64, boxes []box[T]) error {
+	sort.Slice(boxes, func(i, j int) bool {
+		return boxes[i].index < boxes[j].index
+	})
+
+	bw := bufio.NewWriter(w)
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, snapshotMagic...)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(size))
+	buf = binary.LittleEndian.AppendUint64(buf, writeIndex)
+	buf = binary.LittleEndian.AppendUint64(buf, readIndex)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(boxes)))
+	_, err := bw.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range boxes {
+		// Reserve space for index and length.
+		buf, err = codec.Encode(buf[:12], b.data)
+		if err != nil {
+			return err
+		}
+
+		binary.LittleEndian.PutUint64(buf, b.index)
+		binary.LittleEndian.PutUint32(buf[8:], uint32(len(buf)-12))
+		_, err = bw.Write(buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func readSnapshot[T any](r io.Reader, codec Codec[T]) (size int, writeIndex, readIndex uint64, boxes []box[T], err error) {
+	var header [40]byte
+	_, err = io.ReadFull(r, header[:])
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		return
+	}
+	if string(header[:8]) != snapshotMagic {
+		err = fmt.Errorf("%w: bad magic", ErrInvalidSnapshot)
+		return
+	}
+
+	rawSize := binary.LittleEndian.Uint64(header[8:])
+	writeIndex = binary.LittleEndian.Uint64(header[16:])
+	readIndex = binary.LittleEndian.Uint64(header[24:])
+	count := binary.LittleEndian.Uint64(header[32:])
+	if rawSize == 0 || rawSize > snapshotMaxSize || count > rawSize {
+		err = fmt.Errorf("%w: bad size", ErrInvalidSnapshot)
+		return
+	}
+	size = int(rawSize)
+
+	// Counters may have wrapped, compare distances instead of indexes.
+	unread := writeIndex + 1 - readIndex
+
+	// Boxes and values are allocated as they are read so a corrupted count or
+	// length can't allocate more than the snapshot holds.
+	var data bytes.Buffer
+	for i := uint64(0); i < count; i++ {
+		_, err = io.ReadFull(r, header[:12])
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+			return
+		}
+
+		b := box[T]{index: binary.LittleEndian.Uint64(header[:])}
+		if b.index-readIndex >= unread || writeIndex-b.index >= rawSize {
+			err = fmt.Errorf("%w: bad box index", ErrInvalidSnapshot)
+			return
+		}
+
+		length := int64(binary.LittleEndian.Uint32(header[8:]))
+		data.Reset()
+		_, err = io.CopyN(&data, r, length)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+			return
+		}
+
+		b.data, err = codec.Decode(data.Bytes())
+		if err != nil {
+			return
+		}
+		boxes = append(boxes, b)
+	}
+
+	return
+}
@@ -0,0 +1,227 @@
+package ringo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	codec := BinaryCodec[int64]{}
+
+	buffers := map[string]struct {
+		new  func(size int) Buffer[int64]
+		save func(buffer Buffer[int64], buf *bytes.Buffer) error
+		load func(buf *bytes.Buffer) (Buffer[int64], error)
+	}{
+		"Ring": {
+			new: func(size int) Buffer[int64] { return NewRing[int64](size) },
+			save: func(buffer Buffer[int64], buf *bytes.Buffer) error {
+				return buffer.(*Ring[int64]).Save(buf, codec)
+			},
+			load: func(buf *bytes.Buffer) (Buffer[int64], error) {
+				return LoadRing[int64](buf, codec)
+			},
+		},
+		"ManyToOne": {
+			new: func(size int) Buffer[int64] { return NewManyToOne[int64](size) },
+			save: func(buffer Buffer[int64], buf *bytes.Buffer) error {
+				return buffer.(*ManyToOne[int64]).Save(buf, codec)
+			},
+			load: func(buf *bytes.Buffer) (Buffer[int64], error) {
+				return LoadManyToOne[int64](buf, codec)
+			},
+		},
+	}
+
+	for name, b := range buffers {
+		t.Run(name, func(t *testing.T) {
+			t.Run("UnreadValues", func(t *testing.T) {
+				buffer := b.new(100)
+				for i := int64(0); i < 50; i++ {
+					buffer.Push(i)
+				}
+				for i := int64(0); i < 20; i++ {
+					expectNext(t, buffer, i, 0)
+				}
+
+				var buf bytes.Buffer
+				if err := b.save(buffer, &buf); err != nil {
+					t.Fatal(err)
+				}
+				restored, err := b.load(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if restored.Size() != 100 {
+					t.Fatal("restored buffer size doesn't match expected:", restored.Size())
+				}
+				for i := int64(20); i < 50; i++ {
+					expectNext(t, restored, i, 0)
+				}
+				expectEmpty(t, restored)
+
+				// No gap in sequence.
+				restored.Push(50)
+				expectNext(t, restored, 50, 0)
+			})
+
+			t.Run("DroppedData", func(t *testing.T) {
+				buffer := b.new(100)
+				for i := int64(0); i < 1000; i++ {
+					buffer.Push(i)
+				}
+
+				var buf bytes.Buffer
+				if err := b.save(buffer, &buf); err != nil {
+					t.Fatal(err)
+				}
+				restored, err := b.load(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectNext(t, restored, 900, 900)
+				for i := int64(901); i < 1000; i++ {
+					expectNext(t, restored, i, 0)
+				}
+				expectEmpty(t, restored)
+			})
+
+			t.Run("EmptyBuffer", func(t *testing.T) {
+				buffer := b.new(10)
+
+				var buf bytes.Buffer
+				if err := b.save(buffer, &buf); err != nil {
+					t.Fatal(err)
+				}
+				restored, err := b.load(&buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expectEmpty(t, restored)
+				restored.Push(1)
+				expectNext(t, restored, 1, 0)
+			})
+		})
+	}
+
+	t.Run("InvalidSnapshot", func(t *testing.T) {
+		_, err := LoadRing[int64](bytes.NewBufferString("not a snapshot, not a snapshot"), codec)
+		if !errors.Is(err, ErrInvalidSnapshot) {
+			t.Fatal("LoadRing() didn't return ErrInvalidSnapshot:", err)
+		}
+
+		ring := NewRing[int64](10)
+		ring.Push(1)
+		var buf bytes.Buffer
+		if err := ring.Save(&buf, codec); err != nil {
+			t.Fatal(err)
+		}
+		buf.Truncate(buf.Len() - 1)
+
+		_, err = LoadManyToOne[int64](&buf, codec)
+		if !errors.Is(err, ErrInvalidSnapshot) {
+			t.Fatal("LoadManyToOne() of truncated snapshot didn't return ErrInvalidSnapshot:", err)
+		}
+	})
+
+	t.Run("RingOptions", func(t *testing.T) {
+		ring := NewRing[int64](8)
+		var buf bytes.Buffer
+		if err := ring.Save(&buf, codec); err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := LoadRing[int64](&buf, codec, WithRingGrowth[int64](GrowthPolicy{MaxSize: 16}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if restored.growth == nil {
+			t.Fatal("options weren't applied to restored ring")
+		}
+	})
+
+	t.Run("RingWrapped", func(t *testing.T) {
+		// Write index wraps while pushing, slots hold values read before.
+		ring := NewRing[int64](8)
+		ring.writeIndex = math.MaxUint64 - 2
+		ring.readIndex = math.MaxUint64 - 1
+		for i := 0; i < ring.Size(); i++ {
+			seq := ring.writeIndex - uint64(i)
+			ring.buffer[seq%uint64(ring.Size())].index = seq
+		}
+		for i := int64(0); i < 4; i++ {
+			ring.Push(i)
+		}
+
+		var buf bytes.Buffer
+		if err := ring.Save(&buf, codec); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := LoadRing[int64](&buf, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := int64(0); i < 4; i++ {
+			expectNext(t, restored, i, 0)
+		}
+		if err := restored.Seek(0); err != nil {
+			t.Fatal("Seek(0) after restoring a wrapped ring failed:", err)
+		}
+		expectNext(t, restored, 2, 0)
+	})
+
+	t.Run("CorruptedSnapshot", func(t *testing.T) {
+		ring := NewRing[int64](10)
+		ring.Push(1)
+		ring.Push(2)
+		var buf bytes.Buffer
+		if err := ring.Save(&buf, codec); err != nil {
+			t.Fatal(err)
+		}
+		snapshot := buf.Bytes()
+
+		// Snapshot of two values: size at offset 8, box count at 32, index and
+		// length of first box at 40 and 48.
+		corruptions := map[string]func(snapshot []byte){
+			"HugeSize": func(s []byte) {
+				binary.LittleEndian.PutUint64(s[8:], 1<<62)
+			},
+			"HugeCount": func(s []byte) {
+				binary.LittleEndian.PutUint64(s[8:], 1<<20)
+				binary.LittleEndian.PutUint64(s[32:], 1<<20)
+			},
+			"HugeLength": func(s []byte) {
+				binary.LittleEndian.PutUint32(s[48:], 1<<31)
+			},
+			"IndexAlreadyRead": func(s []byte) {
+				binary.LittleEndian.PutUint64(s[40:], 0)
+			},
+			"IndexNotWritten": func(s []byte) {
+				binary.LittleEndian.PutUint64(s[40:], 3)
+			},
+			"IndexOverwritten": func(s []byte) {
+				binary.LittleEndian.PutUint64(s[8:], 1)
+				binary.LittleEndian.PutUint64(s[32:], 1)
+			},
+		}
+
+		for name, corrupt := range corruptions {
+			t.Run(name, func(t *testing.T) {
+				corrupted := append([]byte{}, snapshot...)
+				corrupt(corrupted)
+
+				_, err := LoadRing[int64](bytes.NewReader(corrupted), codec)
+				if !errors.Is(err, ErrInvalidSnapshot) {
+					t.Fatal("LoadRing() didn't return ErrInvalidSnapshot:", err)
+				}
+			})
+		}
+	})
+}
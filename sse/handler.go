@@ -0,0 +1,240 @@
+// Package sse provides an http.Handler streaming values of a ringo GroupRing
+// to clients as Server-Sent Events.
+package sse
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/negrel/ringo"
+)
+
+// Encoder define an encoder of T values. Every ringo.Codec is an Encoder.
+type Encoder[T any] interface {
+	// Encode appends encoded v to dst and returns the extended buffer.
+	Encode(dst []byte, v T) ([]byte, error)
+}
+
+// EncoderFunc is an adapter to allow the use of ordinary functions as
+// Encoder.
+type EncoderFunc[T any] func(dst []byte, v T) ([]byte, error)
+
+// Encode implements Encoder.
+func (ef EncoderFunc[T]) Encode(dst []byte, v T) ([]byte, error) {
+	return ef(dst, v)
+}
+
+// maxPendingBytes is the number of buffered bytes after which events are
+// written even if client didn't catch up.
+const maxPendingBytes = 32 << 10
+
+var _ http.Handler = &Handler[any]{}
+
+// Handler define an http.Handler streaming values pushed to it as
+// Server-Sent Events. Each client reads from its own consumer group of a
+// shared GroupRing, so a slow client drops values instead of blocking others:
+// a "dropped" event whose data is the number of missed values is sent when a
+// client lags. Values that can't be encoded are reported the same way.
+//
+// Every value is sent with its sequence number as event id, clients
+// reconnecting with a Last-Event-ID header resume after that value if it is
+// still held in the ring. Otherwise, stream resumes with live values and
+// missed ones are reported by a "dropped" event.
+type Handler[T any] struct {
+	ring    *ringo.GroupRing[T]
+	encoder Encoder[T]
+	event   string
+
+	clientID     atomic.Uint64
+	encodeErrors atomic.Uint64
+	mu           sync.RWMutex
+	clients      map[chan struct{}]struct{}
+}
+
+// Option can be used to setup the Handler.
+type Option[T any] func(*Handler[T])
+
+// WithEncoder sets encoder of values. Encoded values may span multiple lines.
+// Default is ringo.JSONCodec.
+func WithEncoder[T any](encoder Encoder[T]) Option[T] {
+	return func(h *Handler[T]) {
+		h.encoder = encoder
+	}
+}
+
+// WithEventType sets event type of values. Default is no type, browsers
+// dispatch them as "message" events.
+func WithEventType[T any](event string) Option[T] {
+	return func(h *Handler[T]) {
+		h.event = event
+	}
+}
+
+// NewHandler returns a new Handler backed by a GroupRing of the given size.
+func NewHandler[T any](size int, options ...Option[T]) *Handler[T] {
+	h := &Handler[T]{
+		ring:    ringo.NewGroupRing[T](size),
+		encoder: ringo.JSONCodec[T]{},
+		clients: make(map[chan struct{}]struct{}),
+	}
+
+	for _, opt := range options {
+		opt(h)
+	}
+
+	return h
+}
+
+// Push pushes data to the ring and wakes up clients.
+func (h *Handler[T]) Push(data T) {
+	h.ring.Push(data)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Clients returns number of connected clients.
+func (h *Handler[T]) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients)
+}
+
+// EncodeErrors returns number of values that couldn't be encoded, counted once
+// per client.
+func (h *Handler[T]) EncodeErrors() uint64 {
+	return h.encodeErrors.Load()
+}
+
+// ServeHTTP implements http.Handler. It streams values until request context
+// is done or a write fails.
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	notify := make(chan struct{}, 1)
+	h.mu.Lock()
+	h.clients[notify] = struct{}{}
+	h.mu.Unlock()
+
+	name := "sse-" + strconv.FormatUint(h.clientID.Add(1), 10)
+	group := h.ring.Group(name)
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, notify)
+		h.mu.Unlock()
+		h.ring.Remove(name)
+	}()
+
+	// Sequence number following Last-Event-ID, if any.
+	resumeAt, resuming := uint64(0), false
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		if seq, err := strconv.ParseUint(lastID, 10, 64); err == nil {
+			resumeAt, resuming = seq+1, true
+			// Stream resumes with live values if seq isn't held anymore.
+			_ = group.Seek(resumeAt)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	var buf, data []byte
+	// Values that couldn't be encoded since last event.
+	unencoded := 0
+	for {
+		// Group may never be empty if producers are fast.
+		if ctx.Err() != nil {
+			return
+		}
+
+		value, seq, ok, dropped := group.TryNextSeq()
+		if !ok || len(buf) >= maxPendingBytes {
+			if len(buf) > 0 {
+				if _, err := w.Write(buf); err != nil {
+					return
+				}
+				buf = buf[:0]
+			}
+			flusher.Flush()
+		}
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+			continue
+		}
+
+		// Gap between Last-Event-ID and first value includes values pushed
+		// while client was disconnected.
+		if resuming {
+			resuming = false
+			if seq > resumeAt {
+				dropped = int(seq - resumeAt)
+			}
+		}
+		dropped += unencoded
+		unencoded = 0
+		if dropped > 0 {
+			buf = fmt.Appendf(buf, "event: dropped\ndata: %d\n\n", dropped)
+		}
+
+		var err error
+		data, err = h.encoder.Encode(data[:0], value)
+		if err != nil {
+			// Value is reported as dropped with next one.
+			h.encodeErrors.Add(1)
+			unencoded++
+			continue
+		}
+		buf = appendEvent(buf, seq, h.event, data)
+	}
+}
+
+// appendEvent appends an SSE frame to dst and returns the extended buffer.
+func appendEvent(dst []byte, id uint64, event string, data []byte) []byte {
+	dst = append(dst, "id: "...)
+	dst = strconv.AppendUint(dst, id, 10)
+	dst = append(dst, '\n')
+
+	if event != "" {
+		dst = append(dst, "event: "...)
+		dst = append(dst, event...)
+		dst = append(dst, '\n')
+	}
+
+	for {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		dst = append(dst, "data: "...)
+		dst = append(dst, line...)
+		dst = append(dst, '\n')
+		if !found {
+			break
+		}
+		data = rest
+	}
+
+	return append(dst, '\n')
+}
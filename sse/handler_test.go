@@ -0,0 +1,294 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// event define a parsed SSE frame.
+type event struct {
+	id    string
+	event string
+	data  string
+}
+
+// connect connects to server and returns a function reading next event.
+func connect(t *testing.T, ctx context.Context, url string, lastID string) func() event {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatal("unexpected content type:", ct)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	return func() event {
+		ev := event{}
+		data := []string{}
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				ev.data = strings.Join(data, "\n")
+				return ev
+			}
+
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "id":
+				ev.id = value
+			case "event":
+				ev.event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+
+		t.Fatal("stream closed:", scanner.Err())
+		return ev
+	}
+}
+
+func waitClients[T any](t *testing.T, h *Handler[T], n int) {
+	for i := 0; h.Clients() != n; i++ {
+		if i == 1000 {
+			t.Fatalf("handler has %v clients, expecting %v", h.Clients(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func expectEvent(t *testing.T, ev event, id, typ, data string) {
+	t.Helper()
+
+	if ev.id != id || ev.event != typ || ev.data != data {
+		t.Fatalf("got event %+v, expecting {id:%v event:%v data:%v}", ev, id, typ, data)
+	}
+}
+
+// blockingFlusher blocks first Flush call until unblock is closed and signals
+// following ones on flushed.
+type blockingFlusher struct {
+	*httptest.ResponseRecorder
+	blocked bool
+	unblock chan struct{}
+	flushed chan struct{}
+}
+
+func (bf *blockingFlusher) Flush() {
+	bf.ResponseRecorder.Flush()
+	if bf.blocked {
+		select {
+		case bf.flushed <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	bf.blocked = true
+	<-bf.unblock
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("Stream", func(t *testing.T) {
+		h := NewHandler[map[string]int](100)
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		next := connect(t, context.Background(), srv.URL, "")
+		waitClients(t, h, 1)
+
+		for i := 0; i < 3; i++ {
+			h.Push(map[string]int{"n": i})
+		}
+
+		expectEvent(t, next(), "0", "", `{"n":0}`)
+		expectEvent(t, next(), "1", "", `{"n":1}`)
+		expectEvent(t, next(), "2", "", `{"n":2}`)
+	})
+
+	t.Run("Encoder", func(t *testing.T) {
+		h := NewHandler[string](100,
+			WithEventType[string]("log"),
+			WithEncoder[string](EncoderFunc[string](func(dst []byte, v string) ([]byte, error) {
+				return append(dst, strings.ToUpper(v)...), nil
+			})),
+		)
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		next := connect(t, context.Background(), srv.URL, "")
+		waitClients(t, h, 1)
+
+		h.Push("first line\nsecond line")
+		expectEvent(t, next(), "0", "log", "FIRST LINE\nSECOND LINE")
+	})
+
+	t.Run("EncodeError", func(t *testing.T) {
+		h := NewHandler[int](100, WithEncoder[int](EncoderFunc[int](func(dst []byte, v int) ([]byte, error) {
+			if v == 1 {
+				return dst, errors.New("can't encode 1")
+			}
+			return strconv.AppendInt(dst, int64(v), 10), nil
+		})))
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		next := connect(t, context.Background(), srv.URL, "")
+		waitClients(t, h, 1)
+
+		for i := 0; i < 3; i++ {
+			h.Push(i)
+		}
+
+		expectEvent(t, next(), "0", "", "0")
+		expectEvent(t, next(), "", "dropped", "1")
+		expectEvent(t, next(), "2", "", "2")
+		if h.EncodeErrors() != 1 {
+			t.Fatal("EncodeErrors() returned", h.EncodeErrors())
+		}
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		h := NewHandler[int](4)
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &blockingFlusher{
+			ResponseRecorder: httptest.NewRecorder(),
+			unblock:          make(chan struct{}),
+			flushed:          make(chan struct{}, 1),
+		}
+
+		done := make(chan struct{})
+		go func() {
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+			close(done)
+		}()
+		waitClients(t, h, 1)
+
+		// Client lags while headers are flushed.
+		for i := 0; i < 10; i++ {
+			h.Push(i)
+		}
+		close(w.unblock)
+
+		// Wait for client to catch up.
+		<-w.flushed
+		cancel()
+		<-done
+
+		// Reader jumps to value held in the slot it expected.
+		expected := "event: dropped\ndata: 8\n\n" +
+			"id: 8\ndata: 8\n\n" +
+			"id: 9\ndata: 9\n\n"
+		if body := w.Body.String(); body != expected {
+			t.Fatalf("unexpected stream:\n%v", body)
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		h := NewHandler[int](4)
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		for i := 0; i < 10; i++ {
+			h.Push(i)
+		}
+
+		next := connect(t, context.Background(), srv.URL, "7")
+		expectEvent(t, next(), "8", "", "8")
+		expectEvent(t, next(), "9", "", "9")
+
+		h.Push(10)
+		expectEvent(t, next(), "10", "", "10")
+	})
+
+	t.Run("ResumeUnavailable", func(t *testing.T) {
+		h := NewHandler[int](4)
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		for i := 0; i < 10; i++ {
+			h.Push(i)
+		}
+
+		// Values 3 to 9 were overwritten or pushed while disconnected.
+		next := connect(t, context.Background(), srv.URL, "2")
+		waitClients(t, h, 1)
+
+		h.Push(10)
+		expectEvent(t, next(), "", "dropped", "7")
+		expectEvent(t, next(), "10", "", "10")
+	})
+
+	t.Run("ContextCancellation", func(t *testing.T) {
+		h := NewHandler[int](100)
+		srv := httptest.NewServer(h)
+		t.Cleanup(srv.Close)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		next := connect(t, ctx, srv.URL, "")
+		waitClients(t, h, 1)
+
+		h.Push(1)
+		expectEvent(t, next(), "0", "", "1")
+
+		cancel()
+		waitClients(t, h, 0)
+	})
+
+	t.Run("ContextCancellationWhilePushing", func(t *testing.T) {
+		// Client is slower than producer so its group is never empty.
+		h := NewHandler[int](100, WithEncoder[int](EncoderFunc[int](func(dst []byte, v int) ([]byte, error) {
+			time.Sleep(100 * time.Microsecond)
+			return strconv.AppendInt(dst, int64(v), 10), nil
+		})))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan struct{})
+		go func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+			close(done)
+		}()
+		waitClients(t, h, 1)
+
+		// Producer keeps client group busy.
+		stop := make(chan struct{})
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Push(i)
+				}
+			}
+		}()
+		defer close(stop)
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler didn't stop on context cancellation")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package stats
+
+import "math"
+
+const (
+	// Smallest and largest values with a dedicated bin, smaller (including
+	// zero and negative values) and larger values are clamped.
+	minIndexable = 1e-9
+	maxIndexable = 1e15
+)
+
+// layout define bins of a logarithmic histogram. Bin i (i > 0) holds values in
+// (gamma^(i+offset-1), gamma^(i+offset)], bin 0 holds values smaller or equal
+// to minIndexable.
+type layout struct {
+	gamma    float64
+	lnGamma  float64
+	offset   int
+	binCount int
+}
+
+func newLayout(accuracy float64) layout {
+	if accuracy <= 0 || accuracy >= 1 {
+		panic("relative accuracy must be in (0, 1)")
+	}
+
+	gamma := (1 + accuracy) / (1 - accuracy)
+	l := layout{gamma: gamma, lnGamma: math.Log(gamma)}
+	l.offset = int(math.Ceil(math.Log(minIndexable)/l.lnGamma)) - 1
+	l.binCount = int(math.Ceil(math.Log(maxIndexable)/l.lnGamma)) - l.offset + 1
+
+	return l
+}
+
+// bin returns index of bin holding the given value.
+func (l layout) bin(v float64) int {
+	if v <= minIndexable {
+		return 0
+	}
+
+	if v >= maxIndexable {
+		return l.binCount - 1
+	}
+
+	return int(math.Ceil(math.Log(v)/l.lnGamma)) - l.offset
+}
+
+// value returns representative value of the given bin, its relative error is
+// at most accuracy for values within its bounds.
+func (l layout) value(bin int) float64 {
+	if bin == 0 {
+		return 0
+	}
+
+	return 2 * math.Pow(l.gamma, float64(bin+l.offset)) / (l.gamma + 1)
+}
+
+// quantile returns approximate q-quantile of count values distributed in bins,
+// clamped to [min, max].
+func (l layout) quantile(bins []uint64, count uint64, q, min, max float64) float64 {
+	if q < 0 || q > 1 {
+		panic("quantile must be in [0, 1]")
+	}
+	if count == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(count-1))
+	cumulative := uint64(0)
+	for bin, n := range bins {
+		cumulative += n
+		if cumulative > rank {
+			return math.Min(math.Max(l.value(bin), min), max)
+		}
+	}
+
+	return max
+}
+
+// Histogram define cumulative statistics (count, sum, mean, min, max and
+// approximate quantiles) of all samples added to it. Unlike Window, samples
+// never expire. Quantiles are approximated like Window ones.
+//
+// Histogram isn't safe for concurrent use.
+type Histogram struct {
+	layout layout
+	bins   []uint64
+	count  uint64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+// NewHistogram returns a new empty Histogram whose quantiles have the given
+// relative accuracy (e.g. 0.01 for 1%).
+func NewHistogram(accuracy float64) *Histogram {
+	l := newLayout(accuracy)
+
+	return &Histogram{
+		layout: l,
+		bins:   make([]uint64, l.binCount),
+		min:    math.Inf(1),
+		max:    math.Inf(-1),
+	}
+}
+
+// Add adds a sample to the histogram. NaN samples are ignored.
+func (h *Histogram) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	h.bins[h.layout.bin(v)]++
+	h.count++
+	h.sum += v
+	h.min = math.Min(h.min, v)
+	h.max = math.Max(h.max, v)
+}
+
+// Count returns number of samples.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Sum returns sum of samples.
+func (h *Histogram) Sum() float64 {
+	return h.sum
+}
+
+// Mean returns mean of samples or 0 if histogram is empty.
+func (h *Histogram) Mean() float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.sum / float64(h.count)
+}
+
+// Min returns smallest sample or 0 if histogram is empty.
+func (h *Histogram) Min() float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.min
+}
+
+// Max returns largest sample or 0 if histogram is empty.
+func (h *Histogram) Max() float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.max
+}
+
+// Quantile returns approximate q-quantile (e.g. 0.99 for p99) of samples or 0
+// if histogram is empty. Result is clamped to [Min(), Max()].
+func (h *Histogram) Quantile(q float64) float64 {
+	return h.layout.quantile(h.bins, h.count, q, h.min, h.max)
+}
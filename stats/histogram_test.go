@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	t.Run("RelativeError", func(t *testing.T) {
+		for _, accuracy := range []float64{0.01, 0.05} {
+			l := newLayout(accuracy)
+			for v := 1e-6; v < 1e12; v *= 1.37 {
+				approx := l.value(l.bin(v))
+				if math.Abs(approx-v)/v > accuracy {
+					t.Fatalf("relative error of %v too large: %v", v, approx)
+				}
+			}
+		}
+	})
+
+	t.Run("Clamp", func(t *testing.T) {
+		l := newLayout(0.01)
+		if l.bin(0) != 0 || l.bin(-1) != 0 {
+			t.Fatal("zero and negative values must be in bin 0")
+		}
+		if l.bin(math.Inf(1)) != l.binCount-1 {
+			t.Fatal("large value must be in last bin")
+		}
+	})
+}
+
+func TestHistogram(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		h := NewHistogram(0.01)
+		if h.Count() != 0 || h.Sum() != 0 || h.Mean() != 0 ||
+			h.Min() != 0 || h.Max() != 0 || h.Quantile(0.99) != 0 {
+			t.Fatal("empty histogram statistics must be zero")
+		}
+	})
+
+	t.Run("Aggregates", func(t *testing.T) {
+		h := NewHistogram(0.01)
+		for i := 1; i <= 100; i++ {
+			h.Add(float64(i))
+		}
+		h.Add(math.NaN())
+
+		if h.Count() != 100 || h.Sum() != 5050 || h.Mean() != 50.5 {
+			t.Fatal("count, sum or mean doesn't match expected:", h.Count(), h.Sum(), h.Mean())
+		}
+		if h.Min() != 1 || h.Max() != 100 {
+			t.Fatal("min or max doesn't match expected:", h.Min(), h.Max())
+		}
+	})
+
+	t.Run("Quantile", func(t *testing.T) {
+		h := NewHistogram(0.01)
+		for i := 1; i <= 10000; i++ {
+			h.Add(float64(i))
+		}
+
+		for _, q := range []float64{0, 0.5, 0.9, 0.99, 1} {
+			expected := 1 + q*9999
+			actual := h.Quantile(q)
+			if math.Abs(actual-expected)/expected > 0.011 {
+				t.Fatalf("quantile %v doesn't match expected: %v != %v", q, actual, expected)
+			}
+		}
+	})
+}
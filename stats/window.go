@@ -0,0 +1,273 @@
+// Package stats provides rolling statistics over a sliding time window and
+// cumulative histograms.
+package stats
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket holds samples added during a time slot of a Window.
+type bucket struct {
+	slot  int64
+	count uint64
+	sum   float64
+	min   float64
+	max   float64
+	// Lazily allocated.
+	bins []uint32
+}
+
+func (b *bucket) reset(slot int64) {
+	b.slot = slot
+	b.count = 0
+	b.sum = 0
+	b.min = math.Inf(1)
+	b.max = math.Inf(-1)
+	for i := range b.bins {
+		b.bins[i] = 0
+	}
+}
+
+// Window define rolling statistics (count, sum, mean, min, max and
+// approximate quantiles) of samples added during the last span. Window is a
+// ring of buckets, each covering span/buckets: samples expire a bucket at a
+// time as the window slides. Add is O(1) and queries never rescan samples.
+//
+// Quantiles are approximated using a logarithmic histogram with a bounded
+// relative error (see WithRelativeAccuracy). Zero and negative samples are
+// counted in the lowest bin.
+//
+// Window is safe for concurrent use.
+type Window struct {
+	mu      sync.Mutex
+	width   time.Duration
+	now     func() time.Time
+	layout  layout
+	buckets []bucket
+	// Slot of the most recent bucket.
+	head int64
+	// Aggregates of all live buckets.
+	count uint64
+	bins  []uint64
+}
+
+// Option can be used to setup the Window.
+type Option func(*Window)
+
+// WithClock sets clock used to timestamp samples. Default is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(w *Window) {
+		w.now = now
+	}
+}
+
+// WithRelativeAccuracy sets relative accuracy of quantiles. Default is 0.01
+// (1%). Memory usage is inversely proportional to accuracy.
+func WithRelativeAccuracy(accuracy float64) Option {
+	return func(w *Window) {
+		w.layout = newLayout(accuracy)
+	}
+}
+
+// NewWindow returns a new Window covering the given span, divided into the
+// given number of buckets. Samples expire with a granularity of
+// span/buckets.
+func NewWindow(span time.Duration, buckets int, options ...Option) *Window {
+	if buckets <= 0 {
+		panic("number of buckets can't be negative or zero")
+	}
+	if span < time.Duration(buckets) {
+		panic("window span must be greater than number of buckets")
+	}
+
+	w := &Window{
+		width:   span / time.Duration(buckets),
+		now:     time.Now,
+		layout:  newLayout(0.01),
+		buckets: make([]bucket, buckets),
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	w.bins = make([]uint64, w.layout.binCount)
+	w.head = w.slot(w.now())
+	for i := range w.buckets {
+		w.buckets[i].reset(w.head)
+	}
+
+	return w
+}
+
+// slot returns time slot of t. Slots are negative before 1970, division
+// rounds toward minus infinity so every slot has the same width.
+func (w *Window) slot(t time.Time) int64 {
+	ns := t.UnixNano()
+	slot := ns / int64(w.width)
+	if ns%int64(w.width) < 0 {
+		slot--
+	}
+
+	return slot
+}
+
+// bucket returns bucket of the given slot.
+func (w *Window) bucket(slot int64) *bucket {
+	n := int64(len(w.buckets))
+	return &w.buckets[(slot%n+n)%n]
+}
+
+// advance expires buckets older than the window.
+func (w *Window) advance(slot int64) {
+	if slot <= w.head {
+		return
+	}
+
+	expired := slot - w.head
+	if expired > int64(len(w.buckets)) {
+		expired = int64(len(w.buckets))
+	}
+
+	for s := slot - expired + 1; s <= slot; s++ {
+		b := w.bucket(s)
+		if b.count > 0 {
+			w.count -= b.count
+			for i, n := range b.bins {
+				w.bins[i] -= uint64(n)
+			}
+		}
+		b.reset(s)
+	}
+
+	w.head = slot
+}
+
+// Add adds a sample to the window. NaN samples are ignored.
+func (w *Window) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	slot := w.slot(w.now())
+	w.advance(slot)
+
+	// Sample older than window (e.g. clock went backward).
+	if slot <= w.head-int64(len(w.buckets)) {
+		return
+	}
+
+	b := w.bucket(slot)
+	if b.bins == nil {
+		b.bins = make([]uint32, w.layout.binCount)
+	}
+
+	bin := w.layout.bin(v)
+	b.bins[bin]++
+	b.count++
+	b.sum += v
+	b.min = math.Min(b.min, v)
+	b.max = math.Max(b.max, v)
+
+	w.bins[bin]++
+	w.count++
+}
+
+// Count returns number of samples in the window.
+func (w *Window) Count() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	return w.count
+}
+
+// Sum returns sum of samples in the window.
+func (w *Window) Sum() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	return w.sum()
+}
+
+func (w *Window) sum() float64 {
+	sum := 0.0
+	for i := range w.buckets {
+		sum += w.buckets[i].sum
+	}
+
+	return sum
+}
+
+// Mean returns mean of samples in the window or 0 if window is empty.
+func (w *Window) Mean() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	if w.count == 0 {
+		return 0
+	}
+
+	return w.sum() / float64(w.count)
+}
+
+// Min returns smallest sample in the window or 0 if window is empty.
+func (w *Window) Min() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	return w.min()
+}
+
+func (w *Window) min() float64 {
+	if w.count == 0 {
+		return 0
+	}
+
+	result := math.Inf(1)
+	for i := range w.buckets {
+		result = math.Min(result, w.buckets[i].min)
+	}
+
+	return result
+}
+
+// Max returns largest sample in the window or 0 if window is empty.
+func (w *Window) Max() float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	return w.max()
+}
+
+func (w *Window) max() float64 {
+	if w.count == 0 {
+		return 0
+	}
+
+	result := math.Inf(-1)
+	for i := range w.buckets {
+		result = math.Max(result, w.buckets[i].max)
+	}
+
+	return result
+}
+
+// Quantile returns approximate q-quantile (e.g. 0.99 for p99) of samples in
+// the window or 0 if window is empty. Result is clamped to [Min(), Max()].
+func (w *Window) Quantile(q float64) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance(w.slot(w.now()))
+	return w.layout.quantile(w.bins, w.count, q, w.min(), w.max())
+}
@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+}
+
+func newTestWindow(span time.Duration, buckets int) (*Window, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	return NewWindow(span, buckets, WithClock(clock.Now)), clock
+}
+
+func TestWindow(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		w, _ := newTestWindow(time.Minute, 60)
+		if w.Count() != 0 || w.Sum() != 0 || w.Mean() != 0 ||
+			w.Min() != 0 || w.Max() != 0 || w.Quantile(0.99) != 0 {
+			t.Fatal("empty window statistics must be zero")
+		}
+	})
+
+	t.Run("Aggregates", func(t *testing.T) {
+		w, clock := newTestWindow(time.Minute, 60)
+
+		for i := 1; i <= 100; i++ {
+			w.Add(float64(i))
+			clock.Advance(100 * time.Millisecond)
+		}
+
+		if w.Count() != 100 {
+			t.Fatal("count doesn't match expected:", w.Count())
+		}
+		if w.Sum() != 5050 || w.Mean() != 50.5 {
+			t.Fatal("sum or mean doesn't match expected:", w.Sum(), w.Mean())
+		}
+		if w.Min() != 1 || w.Max() != 100 {
+			t.Fatal("min or max doesn't match expected:", w.Min(), w.Max())
+		}
+	})
+
+	t.Run("Quantile", func(t *testing.T) {
+		w, clock := newTestWindow(time.Minute, 60)
+
+		for i := 1; i <= 10000; i++ {
+			w.Add(float64(i))
+			if i%100 == 0 {
+				clock.Advance(time.Second / 2)
+			}
+		}
+
+		for _, q := range []float64{0, 0.5, 0.9, 0.99, 1} {
+			expected := 1 + q*9999
+			actual := w.Quantile(q)
+			if math.Abs(actual-expected)/expected > 0.011 {
+				t.Fatalf("quantile %v doesn't match expected: %v != %v", q, actual, expected)
+			}
+		}
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		w, clock := newTestWindow(time.Minute, 60)
+
+		// One sample per second during 2 minutes.
+		for i := 0; i < 120; i++ {
+			w.Add(float64(i))
+			clock.Advance(time.Second)
+		}
+
+		// Only last 59 full seconds and the current one remain.
+		if w.Count() != 59 {
+			t.Fatal("count doesn't match expected:", w.Count())
+		}
+		if w.Min() != 61 || w.Max() != 119 {
+			t.Fatal("min or max doesn't match expected:", w.Min(), w.Max())
+		}
+		if p50 := w.Quantile(0.5); math.Abs(p50-90) > 1 {
+			t.Fatal("median doesn't match expected:", p50)
+		}
+
+		clock.Advance(time.Hour)
+		if w.Count() != 0 || w.Sum() != 0 || w.Quantile(0.5) != 0 {
+			t.Fatal("samples didn't expire")
+		}
+
+		w.Add(42)
+		if w.Count() != 1 || w.Quantile(0.99) != 42 {
+			t.Fatal("window doesn't match expected after expiration")
+		}
+	})
+
+	t.Run("BeforeEpoch", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(1969, 12, 31, 23, 59, 30, 0, time.UTC)}
+		w := NewWindow(time.Minute, 60, WithClock(clock.Now))
+
+		// Window slides across 1970.
+		for i := 1; i <= 60; i++ {
+			w.Add(float64(i))
+			clock.Advance(time.Second)
+		}
+
+		if w.Count() != 59 || w.Min() != 2 || w.Max() != 60 {
+			t.Fatal("statistics don't match expected:", w.Count(), w.Min(), w.Max())
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		w := NewWindow(time.Minute, 60)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					w.Add(1)
+					w.Quantile(0.99)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if w.Count() != 8000 {
+			t.Fatal("count doesn't match expected:", w.Count())
+		}
+	})
+}
+
+func BenchmarkWindowAdd(b *testing.B) {
+	w := NewWindow(time.Minute, 60)
+	for i := 0; i < b.N; i++ {
+		w.Add(float64(i % 1000))
+	}
+}
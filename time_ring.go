@@ -0,0 +1,118 @@
+package ringo
+
+import "time"
+
+type timedValue[T any] struct {
+	at   time.Time
+	data T
+}
+
+var _ Buffer[any] = &TimeRing[any]{}
+
+// TimeRing define a Ring whose values expire after a time to live. Values are
+// timestamped on Push and expired values are skipped on read.
+type TimeRing[T any] struct {
+	ring *Ring[timedValue[T]]
+	ttl  time.Duration
+	now  func() time.Time
+
+	// Values lost since last read.
+	dropped int
+	expired int
+}
+
+// TimeRingOption can be used to setup the TimeRing.
+type TimeRingOption[T any] func(*TimeRing[T])
+
+// WithTimeRingClock sets clock used to timestamp values. Default is
+// time.Now.
+func WithTimeRingClock[T any](now func() time.Time) TimeRingOption[T] {
+	return func(tr *TimeRing[T]) {
+		tr.now = now
+	}
+}
+
+// NewTimeRing returns a new TimeRing with the given size whose values expire
+// after ttl.
+func NewTimeRing[T any](size int, ttl time.Duration, options ...TimeRingOption[T]) *TimeRing[T] {
+	tr := &TimeRing[T]{
+		ring: NewRing[timedValue[T]](size),
+		ttl:  ttl,
+		now:  time.Now,
+	}
+
+	for _, opt := range options {
+		opt(tr)
+	}
+
+	return tr
+}
+
+// Size implements Buffer.
+func (tr *TimeRing[T]) Size() int {
+	return tr.ring.Size()
+}
+
+// Push implements Buffer.
+func (tr *TimeRing[T]) Push(data T) {
+	tr.ring.Push(timedValue[T]{tr.now(), data})
+}
+
+// TryNext implements Buffer. Dropped values includes both overwritten and
+// expired values.
+func (tr *TimeRing[T]) TryNext() (result T, ok bool, dropped int) {
+	result, _, ok, dropped, expired := tr.TryNextTimed()
+	return result, ok, dropped + expired
+}
+
+// TryNextTimed is like TryNext but also returns the timestamp of the read
+// value and reports overwritten (dropped) and expired values separately.
+func (tr *TimeRing[T]) TryNextTimed() (result T, at time.Time, ok bool, dropped int, expired int) {
+	cutoff := tr.now().Add(-tr.ttl)
+
+	for {
+		value, found, d := tr.ring.TryNext()
+		tr.dropped += d
+		if !found {
+			return
+		}
+
+		if value.at.Before(cutoff) {
+			tr.expired++
+			continue
+		}
+
+		dropped, expired = tr.dropped, tr.expired
+		tr.dropped, tr.expired = 0, 0
+
+		return value.data, value.at, true, dropped, expired
+	}
+}
+
+// Window calls fn on every non expired value pushed since the given time, in
+// push order, until fn returns false. Read cursor isn't moved, so values
+// already read are included.
+func (tr *TimeRing[T]) Window(since time.Time, fn func(at time.Time, data T) bool) {
+	if cutoff := tr.now().Add(-tr.ttl); since.Before(cutoff) {
+		since = cutoff
+	}
+
+	r := tr.ring
+	size := uint64(r.Size())
+	// Sequence 0 is never written.
+	first := uint64(1)
+	if r.writeIndex > size {
+		first = r.writeIndex - size + 1
+	}
+
+	for seq := first; seq <= r.writeIndex; seq++ {
+		box := r.buffer[seq%size]
+		if box.index != seq || box.data.at.Before(since) {
+			continue
+		}
+
+		if !fn(box.data.at, box.data.data) {
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ringo
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+func TestTimeRing(t *testing.T) {
+	newTimeRing := func(size int, ttl time.Duration) (*TimeRing[int], *fakeClock) {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		return NewTimeRing(size, ttl, WithTimeRingClock[int](clock.Now)), clock
+	}
+
+	t.Run("SequentialReadWrite", func(t *testing.T) {
+		buffer, clock := newTimeRing(100, time.Minute)
+
+		for i := 0; i < 1000; i++ {
+			buffer.Push(i)
+			clock.Advance(time.Second)
+
+			next, at, ok, dropped, expired := buffer.TryNextTimed()
+			if !ok {
+				t.Fatal("TryNextTimed() returned false, expecting true")
+			}
+			if next != i || dropped != 0 || expired != 0 {
+				t.Fatal("value read from buffer doesn't match expected")
+			}
+			if !at.Equal(clock.Now().Add(-time.Second)) {
+				t.Fatal("timestamp doesn't match expected:", at)
+			}
+		}
+	})
+
+	t.Run("ExpiredData", func(t *testing.T) {
+		buffer, clock := newTimeRing(100, 10*time.Second)
+
+		for i := 0; i < 20; i++ {
+			buffer.Push(i)
+			clock.Advance(time.Second)
+		}
+
+		// Values pushed at 0s..9s are older than 10s.
+		next, _, ok, dropped, expired := buffer.TryNextTimed()
+		if !ok {
+			t.Fatal("TryNextTimed() returned false, expecting true")
+		}
+		if next != 10 || dropped != 0 || expired != 10 {
+			t.Fatal("buffer reported wrong number of expired value:", next, dropped, expired)
+		}
+
+		clock.Advance(time.Minute)
+		_, ok, dropped = buffer.TryNext()
+		if ok {
+			t.Fatal("TryNext() returned an expired value")
+		}
+
+		buffer.Push(42)
+		next, ok, dropped = buffer.TryNext()
+		if !ok || next != 42 || dropped != 9 {
+			t.Fatal("expired value not reported as dropped by TryNext():", dropped)
+		}
+	})
+
+	t.Run("OverwrittenAndExpiredData", func(t *testing.T) {
+		buffer, clock := newTimeRing(10, 5*time.Second)
+
+		for i := 0; i < 20; i++ {
+			buffer.Push(i)
+			clock.Advance(time.Second)
+		}
+
+		// 0..9 are overwritten, 10..14 expired.
+		next, _, ok, dropped, expired := buffer.TryNextTimed()
+		if !ok || next != 15 || dropped != 10 || expired != 5 {
+			t.Fatal("buffer reported wrong number of dropped or expired value:", next, dropped, expired)
+		}
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		buffer, clock := newTimeRing(10, 15*time.Second)
+
+		for i := 0; i < 20; i++ {
+			buffer.Push(i)
+			clock.Advance(time.Second)
+		}
+		// Read doesn't change window.
+		buffer.TryNext()
+
+		values := []int{}
+		buffer.Window(clock.Now().Add(-8*time.Second), func(_ time.Time, data int) bool {
+			values = append(values, data)
+			return true
+		})
+		if len(values) != 8 || values[0] != 12 || values[7] != 19 {
+			t.Fatal("window values doesn't match expected:", values)
+		}
+
+		// Expired values are excluded.
+		clock.Advance(7 * time.Second)
+		values = values[:0]
+		buffer.Window(time.Time{}, func(_ time.Time, data int) bool {
+			values = append(values, data)
+			return true
+		})
+		if len(values) != 8 || values[0] != 12 {
+			t.Fatal("window values doesn't match expected:", values)
+		}
+
+		// Stop iteration.
+		values = values[:0]
+		buffer.Window(time.Time{}, func(_ time.Time, data int) bool {
+			values = append(values, data)
+			return false
+		})
+		if len(values) != 1 {
+			t.Fatal("window iteration didn't stop")
+		}
+	})
+}
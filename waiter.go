@@ -10,6 +10,14 @@ type Waiter[T any] struct {
 	Buffer[T]
 	c   chan struct{}
 	ctx context.Context
+	// Channel is shared with other readers, pass signal on after a read.
+	shared bool
+}
+
+// signaler is implemented by buffers signaling pushes done without their
+// Waiter. Waiter waits on the returned channel.
+type signaler interface {
+	signal() chan struct{}
 }
 
 // WaiterConfigOption can be used to setup the waiter.
@@ -33,6 +41,10 @@ func NewWaiter[T any](buffer Buffer[T], opts ...WaiterConfigOption[T]) Waiter[T]
 	}
 	w.Buffer = buffer
 	w.c = make(chan struct{}, 1)
+	if s, ok := buffer.(signaler); ok {
+		w.c = s.signal()
+		w.shared = true
+	}
 
 	for _, opt := range opts {
 		opt(&w)
@@ -64,6 +76,9 @@ func (w *Waiter[T]) Next() (next T, done bool, dropped int) {
 	for {
 		next, ok, dropped = w.Buffer.TryNext()
 		if ok {
+			if w.shared {
+				w.broadcast()
+			}
 			return
 		}
 		select {